@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// sniffLength is the number of leading bytes inspected to tell text from binary
+const sniffLength = 8192
+
+// maxInvalidUTF8 is the share of invalid UTF-8 bytes above which a file is binary
+const maxInvalidUTF8 = 0.1
+
+// textMIMETypes are the non-text/* types returned by http.DetectContentType for text files
+var textMIMETypes = []string{
+	"application/json",
+	"application/xml",
+	"application/javascript",
+	"application/x-javascript",
+	"image/svg+xml",
+}
+
+// lockFileNames are generated dependency lock files, which are long and not worth reading
+var lockFileNames = map[string]bool{
+	"package-lock.json":   true,
+	"npm-shrinkwrap.json": true,
+	"yarn.lock":           true,
+	"pnpm-lock.yaml":      true,
+	"bun.lockb":           true,
+	"go.sum":              true,
+	"Cargo.lock":          true,
+	"Gemfile.lock":        true,
+	"composer.lock":       true,
+	"poetry.lock":         true,
+	"Pipfile.lock":        true,
+	"uv.lock":             true,
+}
+
+// dependencyDirs hold third-party code and are skipped as a whole
+var dependencyDirs = map[string]bool{
+	"node_modules":     true,
+	"bower_components": true,
+	"jspm_packages":    true,
+	"__pycache__":      true,
+}
+
+// minifiedSuffixes mark bundled or minified files by name
+var minifiedSuffixes = []string{".min.js", ".min.css", ".bundle.js", ".chunk.js", ".map"}
+
+// maxAverageLineLength is the average line length above which a file is treated as minified
+const maxAverageLineLength = 300
+
+// detectBinary inspects the start of a file and describes why it looks binary,
+// or returns "" for text: NUL bytes, too much invalid UTF-8 or a non-text MIME type
+func detectBinary(content []byte) string {
+	sample := content
+	if len(sample) > sniffLength {
+		sample = sample[:sniffLength]
+	}
+	if len(sample) == 0 {
+		return ""
+	}
+
+	if bytes.IndexByte(sample, 0) >= 0 {
+		return "contains NUL bytes"
+	}
+
+	invalid := 0
+	for i := 0; i < len(sample); {
+		r, size := utf8.DecodeRune(sample[i:])
+		// A rune cut off by the sample's end is not invalid
+		if r == utf8.RuneError && size == 1 && len(sample)-i >= utf8.UTFMax {
+			invalid++
+		}
+		i += size
+	}
+	if ratio := float64(invalid) / float64(len(sample)); ratio > maxInvalidUTF8 {
+		return fmt.Sprintf("%.0f%% invalid UTF-8", ratio*100)
+	}
+
+	mime := http.DetectContentType(sample)
+	if !isTextMIME(mime) {
+		return fmt.Sprintf("MIME type %s", mime)
+	}
+	return ""
+}
+
+// isTextMIME reports whether a detected content type is text
+func isTextMIME(mime string) bool {
+	mime, _, _ = strings.Cut(mime, ";")
+	if strings.HasPrefix(mime, "text/") {
+		return true
+	}
+	for _, textType := range textMIMETypes {
+		if mime == textType {
+			return true
+		}
+	}
+	return false
+}
+
+// isMinified reports whether a file is a bundle or minified by name or by its line length
+func isMinified(name string, content []byte) bool {
+	for _, suffix := range minifiedSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	if len(content) < sniffLength {
+		return false
+	}
+	lines := bytes.Count(content, []byte("\n")) + 1
+	return len(content)/lines > maxAverageLineLength
+}
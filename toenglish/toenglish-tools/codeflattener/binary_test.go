@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDetectBinary(t *testing.T) {
+	png := append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte("IHDR"), 10)...)
+	latin1 := bytes.Repeat([]byte("caf\xe9 "), 100)
+	// A multibyte rune cut by the sniff length is not invalid
+	cutRune := append(bytes.Repeat([]byte("a"), sniffLength-1), "é and more"...)
+
+	tests := []struct {
+		name    string
+		content []byte
+		want    string
+	}{
+		{"empty", nil, ""},
+		{"source", []byte("package main\n\nfunc main() {}\n"), ""},
+		{"utf-8", []byte("héllo wörld, ünïcode ✓\n"), ""},
+		{"json", []byte(`{"name": "app", "version": "1.0"}`), ""},
+		{"svg", []byte(`<svg xmlns="http://www.w3.org/2000/svg"></svg>`), ""},
+		{"cut rune", cutRune, ""},
+		{"nul byte", []byte("text\x00more"), "contains NUL bytes"},
+		{"latin-1", latin1, "20% invalid UTF-8"},
+		{"png", png, "MIME type image/png"},
+		{"pdf", []byte("%PDF-1.7\n"), "MIME type application/pdf"},
+	}
+	for _, test := range tests {
+		if got := detectBinary(test.content); got != test.want {
+			t.Errorf("%s: detectBinary = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestIsMinified(t *testing.T) {
+	longLine := strings.Repeat("var a=1;", sniffLength/8+10)
+	code := strings.Repeat("func main() {\n\tfmt.Println(\"hello\")\n}\n", 300)
+	tests := []struct {
+		name    string
+		content string
+		want    bool
+	}{
+		{"app.min.js", "var a=1;", true},
+		{"style.min.css", "", true},
+		{"main.bundle.js", "", true},
+		{"app.js.map", "", true},
+		{"app.js", "var a=1;", false},
+
+		// Long lines only count in files big enough to judge
+		{"app.js", longLine, true},
+		{"app.js", longLine[:sniffLength-1], false},
+		{"main.go", code, false},
+	}
+	for _, test := range tests {
+		if got := isMinified(test.name, []byte(test.content)); got != test.want {
+			t.Errorf("isMinified(%q, %d bytes) = %v, want %v", test.name, len(test.content), got, test.want)
+		}
+	}
+}
@@ -0,0 +1,327 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// charsPerToken is the average number of characters per token used to estimate
+// token counts; it is close to what common LLM tokenizers give for source code
+const charsPerToken = 4
+
+// EstimateTokens estimates the number of LLM tokens in text
+func EstimateTokens(text []byte) int {
+	return (utf8.RuneCount(text) + charsPerToken - 1) / charsPerToken
+}
+
+// ManifestFile is one file, or one part of a file, in a chunk
+type ManifestFile struct {
+	Path   string `json:"path"`
+	Tokens int    `json:"tokens"`
+	Part   int    `json:"part,omitempty"`
+	Parts  int    `json:"parts,omitempty"`
+}
+
+// ManifestChunk lists the files written to one chunk
+type ManifestChunk struct {
+	Number int            `json:"number"`
+	File   string         `json:"file"`
+	Tokens int            `json:"tokens"`
+	Files  []ManifestFile `json:"files"`
+}
+
+// Manifest describes how the files were split into chunks
+type Manifest struct {
+	Budget        int             `json:"budget"`
+	CharsPerToken int             `json:"charsPerToken"`
+	TotalTokens   int             `json:"totalTokens"`
+	Chunks        []ManifestChunk `json:"chunks"`
+}
+
+// ChunkWriter writes files into numbered chunk files of at most Budget estimated
+// tokens. Files go in walk order and are only split when they don't fit in a
+// chunk on their own.
+type ChunkWriter struct {
+	Budget int
+
+	output   string
+	format   string
+	file     *os.File
+	buffered *bufio.Writer
+	writer   Writer
+	manifest Manifest
+}
+
+// NewChunkWriter creates a writer whose chunks are named after the output path,
+// e.g. combined_code_001.txt for combined_code.txt. Every chunk is a complete
+// document in the format. The budget must leave room for some content after
+// the format's start and end and a file header.
+func NewChunkWriter(output, format string, budget int) (*ChunkWriter, error) {
+	if _, err := NewWriter(format, &bytes.Buffer{}); err != nil {
+		return nil, err
+	}
+	cw := &ChunkWriter{
+		Budget:   budget,
+		output:   output,
+		format:   format,
+		manifest: Manifest{Budget: budget, CharsPerToken: charsPerToken, Chunks: []ManifestChunk{}},
+	}
+	if minimum := cw.overhead() + cw.headerTokens(""); budget <= minimum {
+		return nil, fmt.Errorf("chunk budget of %d tokens is too small, the %s format needs more than %d", budget, format, minimum)
+	}
+	return cw, nil
+}
+
+// ChunkPath returns the path of the n-th chunk of an output path
+func ChunkPath(output string, n int) string {
+	ext := filepath.Ext(output)
+	return fmt.Sprintf("%s_%03d%s", strings.TrimSuffix(output, ext), n, ext)
+}
+
+// ManifestPath returns the path of the manifest of an output path
+func ManifestPath(output string) string {
+	ext := filepath.Ext(output)
+	return strings.TrimSuffix(output, ext) + "_manifest.json"
+}
+
+// ChunkGlobs return name globs matching the chunks and manifest of an output path,
+// so a later run doesn't flatten them
+func ChunkGlobs(output string) []string {
+	ext := filepath.Ext(output)
+	base := strings.TrimSuffix(filepath.Base(output), ext)
+	return []string{base + "_[0-9][0-9][0-9]*" + ext, base + "_manifest.json"}
+}
+
+// RemoveStaleChunks deletes the chunks of an earlier run, which may have had more of them
+func RemoveStaleChunks(output string) error {
+	for n := 1; ; n++ {
+		err := os.Remove(ChunkPath(output, n))
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error removing old chunk: %v", err)
+		}
+	}
+}
+
+// Write adds a file to the current chunk, starting a new chunk when it doesn't fit
+func (cw *ChunkWriter) Write(file SourceFile) error {
+	tokens := EstimateTokens(cw.render(file))
+	if tokens+cw.overhead() <= cw.Budget {
+		if cw.file == nil || cw.currentTokens()+tokens > cw.Budget {
+			if err := cw.startChunk(); err != nil {
+				return err
+			}
+		}
+		return cw.writeEntry(file, ManifestFile{Path: file.Path, Tokens: tokens})
+	}
+
+	// Too big for any chunk: each part starts a chunk, and the next files may follow the last part
+	room := cw.Budget - cw.overhead() - cw.headerTokens(file.Path) - 8
+	if room < 1 {
+		return fmt.Errorf("chunk budget of %d tokens is too small for %s", cw.Budget, file.Path)
+	}
+	parts := splitByTokens(file.Content, room)
+	// Escaping (JSON, XML) makes a part longer than its raw content, so split finer until every part fits
+	for room > 1 && !cw.partsFit(file, parts) {
+		room = room * 9 / 10
+		parts = splitByTokens(file.Content, room)
+	}
+	if !cw.partsFit(file, parts) {
+		return fmt.Errorf("can't split %s into parts of at most %d tokens", file.Path, cw.Budget)
+	}
+	for i, content := range parts {
+		part := filePart(file, content, i, len(parts))
+		if err := cw.startChunk(); err != nil {
+			return err
+		}
+		entry := ManifestFile{
+			Path:   file.Path,
+			Tokens: EstimateTokens(cw.render(part)),
+			Part:   i + 1,
+			Parts:  len(parts),
+		}
+		if err := cw.writeEntry(part, entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// partsFit reports whether every part of the file fits in a chunk of its own
+func (cw *ChunkWriter) partsFit(file SourceFile, parts [][]byte) bool {
+	for i, content := range parts {
+		if EstimateTokens(cw.render(filePart(file, content, i, len(parts))))+cw.overhead() > cw.Budget {
+			return false
+		}
+	}
+	return true
+}
+
+// filePart returns the i-th of n parts of a file, named "<path> (part i of n)"
+func filePart(file SourceFile, content []byte, i, n int) SourceFile {
+	return SourceFile{
+		Path:    fmt.Sprintf("%s (part %d of %d)", file.Path, i+1, n),
+		AbsPath: file.AbsPath,
+		Size:    int64(len(content)),
+		Content: content,
+	}
+}
+
+// Close finishes the last chunk
+func (cw *ChunkWriter) Close() error {
+	return cw.closeChunk()
+}
+
+// Manifest returns the chunks written so far
+func (cw *ChunkWriter) Manifest() Manifest {
+	return cw.manifest
+}
+
+// WriteManifest writes the manifest as JSON next to the chunks
+func (cw *ChunkWriter) WriteManifest() (string, error) {
+	path := ManifestPath(cw.output)
+	data, err := json.MarshalIndent(cw.manifest, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("error encoding manifest: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("error writing manifest: %v", err)
+	}
+	return path, nil
+}
+
+// currentTokens returns the estimated tokens of the open chunk
+func (cw *ChunkWriter) currentTokens() int {
+	return cw.manifest.Chunks[len(cw.manifest.Chunks)-1].Tokens
+}
+
+// startChunk closes the open chunk and creates the next one
+func (cw *ChunkWriter) startChunk() error {
+	if err := cw.closeChunk(); err != nil {
+		return err
+	}
+
+	number := len(cw.manifest.Chunks) + 1
+	path := ChunkPath(cw.output, number)
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("error creating chunk: %v", err)
+	}
+	cw.file = file
+	cw.buffered = bufio.NewWriter(file)
+	if cw.writer, err = NewWriter(cw.format, cw.buffered); err != nil {
+		return err
+	}
+	if err := cw.writer.Begin(); err != nil {
+		return fmt.Errorf("error writing chunk: %v", err)
+	}
+
+	overhead := cw.overhead()
+	cw.manifest.Chunks = append(cw.manifest.Chunks, ManifestChunk{Number: number, File: filepath.Base(path), Tokens: overhead, Files: []ManifestFile{}})
+	cw.manifest.TotalTokens += overhead
+	return nil
+}
+
+// closeChunk flushes and closes the open chunk, if any
+func (cw *ChunkWriter) closeChunk() error {
+	if cw.file == nil {
+		return nil
+	}
+	err := cw.writer.End()
+	if err == nil {
+		err = cw.buffered.Flush()
+	}
+	if closeErr := cw.file.Close(); err == nil {
+		err = closeErr
+	}
+	cw.file, cw.buffered, cw.writer = nil, nil, nil
+	if err != nil {
+		return fmt.Errorf("error writing chunk: %v", err)
+	}
+	return nil
+}
+
+// writeEntry writes a file to the open chunk and records it in the manifest
+func (cw *ChunkWriter) writeEntry(file SourceFile, entry ManifestFile) error {
+	if err := cw.writer.WriteFile(file); err != nil {
+		return err
+	}
+	chunk := &cw.manifest.Chunks[len(cw.manifest.Chunks)-1]
+	chunk.Files = append(chunk.Files, entry)
+	chunk.Tokens += entry.Tokens
+	cw.manifest.TotalTokens += entry.Tokens
+	return nil
+}
+
+// render returns a file as the chunk's writer writes it, for estimating its tokens
+func (cw *ChunkWriter) render(file SourceFile) []byte {
+	var text bytes.Buffer
+	if writer, err := NewWriter(cw.format, &text); err == nil {
+		writer.WriteFile(file)
+	}
+	return text.Bytes()
+}
+
+// headerTokens returns the estimated tokens of an empty file with the path, i.e. its header
+func (cw *ChunkWriter) headerTokens(path string) int {
+	return EstimateTokens(cw.render(SourceFile{Path: path}))
+}
+
+// overhead returns the estimated tokens a chunk spends on the format's start and end
+func (cw *ChunkWriter) overhead() int {
+	var text bytes.Buffer
+	if writer, err := NewWriter(cw.format, &text); err == nil {
+		writer.Begin()
+		writer.End()
+	}
+	return EstimateTokens(text.Bytes())
+}
+
+// splitByTokens cuts content into parts of at most budget estimated tokens,
+// at line ends where possible and inside a line only when the line is too long
+func splitByTokens(content []byte, budget int) [][]byte {
+	budget = max(budget, 1)
+	maxBytes := budget * charsPerToken
+
+	parts := [][]byte{}
+	var part []byte
+	for len(content) > 0 {
+		line := content
+		if i := bytes.IndexByte(content, '\n'); i >= 0 {
+			line = content[:i+1]
+		}
+		content = content[len(line):]
+
+		for EstimateTokens(line) > budget {
+			cut := maxBytes
+			// Don't cut a UTF-8 sequence in two
+			for cut > 0 && !utf8.RuneStart(line[cut]) {
+				cut--
+			}
+			if len(part) > 0 {
+				parts = append(parts, part)
+				part = nil
+			}
+			parts = append(parts, line[:cut])
+			line = line[cut:]
+		}
+
+		if len(part) > 0 && EstimateTokens(part)+EstimateTokens(line) > budget {
+			parts = append(parts, part)
+			part = nil
+		}
+		part = append(part, line...)
+	}
+	if len(part) > 0 {
+		parts = append(parts, part)
+	}
+	return parts
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEstimateTokens(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"", 0},
+		{"a", 1},
+		{"abcd", 1},
+		{"abcde", 2},
+		{"héllo wörld", 3},
+	}
+	for _, test := range tests {
+		if got := EstimateTokens([]byte(test.text)); got != test.want {
+			t.Errorf("EstimateTokens(%q) = %d, want %d", test.text, got, test.want)
+		}
+	}
+}
+
+func TestSplitByTokens(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		budget  int
+		parts   int
+	}{
+		{"fits", "one\ntwo\n", 10, 1},
+		{"at line ends", "aaaaaaa\nbbbbbbb\nccccccc\n", 2, 3},
+		{"long line", strings.Repeat("x", 40), 2, 5},
+		{"no final newline", "aaaaaaa\nbbb", 2, 2},
+		{"multibyte", strings.Repeat("é", 10), 1, 4},
+		{"zero budget", "abc", 0, 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			parts := splitByTokens([]byte(test.content), test.budget)
+			if len(parts) != test.parts {
+				t.Errorf("got %d parts, want %d: %q", len(parts), test.parts, parts)
+			}
+			if joined := bytes.Join(parts, nil); string(joined) != test.content {
+				t.Errorf("parts join to %q, want %q", joined, test.content)
+			}
+			for _, part := range parts {
+				if EstimateTokens(part) > max(test.budget, 1) {
+					t.Errorf("part %q has %d tokens, over the budget of %d", part, EstimateTokens(part), test.budget)
+				}
+			}
+		})
+	}
+}
+
+func TestChunkWriterBudget(t *testing.T) {
+	small := strings.Repeat("small line\n", 5)
+	large := strings.Repeat("a much longer line of code\n", 200)
+	tests := []struct {
+		name   string
+		format string
+		budget int
+		files  []string
+		chunks int
+		split  bool
+	}{
+		{"one chunk", FormatText, 1000, []string{small, small}, 1, false},
+		{"new chunk when full", FormatText, 30, []string{small, small, small}, 3, false},
+		{"split large file", FormatText, 500, []string{large}, 3, true},
+		{"split markdown", FormatMarkdown, 500, []string{small, large}, 4, true},
+		{"split json", FormatJSON, 500, []string{large, small}, 4, true},
+		{"split xml", FormatXML, 500, []string{large}, 4, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output := filepath.Join(t.TempDir(), "combined"+FormatExtension(test.format))
+			chunks, err := NewChunkWriter(output, test.format, test.budget)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, content := range test.files {
+				file := SourceFile{Path: filepath.Join("dir", string(rune('a'+i))+".go"), Size: int64(len(content)), Content: []byte(content)}
+				if err := chunks.Write(file); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := chunks.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			manifest := chunks.Manifest()
+			if len(manifest.Chunks) != test.chunks {
+				t.Errorf("got %d chunks, want %d", len(manifest.Chunks), test.chunks)
+			}
+			split := false
+			for _, chunk := range manifest.Chunks {
+				data, err := os.ReadFile(filepath.Join(filepath.Dir(output), chunk.File))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if tokens := EstimateTokens(data); tokens > test.budget {
+					t.Errorf("%s has %d tokens, over the budget of %d", chunk.File, tokens, test.budget)
+				}
+				for _, file := range chunk.Files {
+					split = split || file.Parts > 0
+				}
+			}
+			if split != test.split {
+				t.Errorf("split = %v, want %v", split, test.split)
+			}
+		})
+	}
+}
+
+func TestChunkPaths(t *testing.T) {
+	tests := []struct {
+		output   string
+		chunk    string
+		manifest string
+	}{
+		{"combined_code.txt", "combined_code_002.txt", "combined_code_manifest.json"},
+		{filepath.Join("out", "code.md"), filepath.Join("out", "code_002.md"), filepath.Join("out", "code_manifest.json")},
+		{"noext", "noext_002", "noext_manifest.json"},
+	}
+	for _, test := range tests {
+		if got := ChunkPath(test.output, 2); got != test.chunk {
+			t.Errorf("ChunkPath(%q) = %q, want %q", test.output, got, test.chunk)
+		}
+		if got := ManifestPath(test.output); got != test.manifest {
+			t.Errorf("ManifestPath(%q) = %q, want %q", test.output, got, test.manifest)
+		}
+		globs := ChunkGlobs(test.output)
+		if matched, _ := filepath.Match(globs[0], filepath.Base(test.chunk)); !matched {
+			t.Errorf("ChunkGlobs(%q)[0] = %q doesn't match %q", test.output, globs[0], test.chunk)
+		}
+	}
+}
+
+func TestChunkWriterTooSmall(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		budget int
+		path   string
+	}{
+		{"json", FormatJSON, 5, "go.mod"},
+		{"xml", FormatXML, 10, "go.mod"},
+		{"long path", FormatText, 20, strings.Repeat("deep/", 20) + "go.mod"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output := filepath.Join(t.TempDir(), "combined"+FormatExtension(test.format))
+			chunks, err := NewChunkWriter(output, test.format, test.budget)
+			if err == nil {
+				err = chunks.Write(SourceFile{Path: test.path, Content: []byte("module example.com/app\n\ngo 1.23\n")})
+			}
+			if err == nil || !strings.Contains(err.Error(), "too small") {
+				t.Errorf("got %v, want a budget error", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new string
+		want     string
+	}{
+		{"equal", "a\nb\n", "a\nb\n", ""},
+		{
+			"changed line",
+			"a\nb\nc\n", "a\nB\nc\n",
+			"--- a/f\n+++ b/f\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n",
+		},
+		{
+			"added file",
+			"", "new\n",
+			"--- a/f\n+++ b/f\n@@ -0,0 +1,1 @@\n+new\n",
+		},
+		{
+			"deleted lines",
+			"a\nb\n", "",
+			"--- a/f\n+++ b/f\n@@ -1,2 +0,0 @@\n-a\n-b\n",
+		},
+		{
+			"final newline removed",
+			"a\nb\n", "a\nb",
+			"--- a/f\n+++ b/f\n@@ -1,2 +1,2 @@\n a\n-b\n+b\n\\ No newline at end of file\n",
+		},
+		{
+			"final newline added",
+			"a\nb", "a\nb\n",
+			"--- a/f\n+++ b/f\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+b\n",
+		},
+		{
+			"both without final newline",
+			"a\nb", "A\nb",
+			"--- a/f\n+++ b/f\n@@ -1,2 +1,2 @@\n-a\n+A\n b\n\\ No newline at end of file\n",
+		},
+		{
+			"context only around changes",
+			"1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n", "1\n2\n3\n4\n5\nsix\n7\n8\n9\n10\n",
+			"--- a/f\n+++ b/f\n@@ -3,7 +3,7 @@\n 3\n 4\n 5\n-6\n+six\n 7\n 8\n 9\n",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var out bytes.Buffer
+			UnifiedDiff(&out, "a/f", "b/f", []byte(test.old), []byte(test.new))
+			if out.String() != test.want {
+				t.Errorf("got\n%s\nwant\n%s", out.String(), test.want)
+			}
+		})
+	}
+}
+
+func TestUnifiedDiffHunks(t *testing.T) {
+	old := []string{}
+	for i := 0; i < 30; i++ {
+		old = append(old, "line")
+	}
+	changed := append([]string{}, old...)
+	changed[2] = "first"
+	changed[25] = "second"
+
+	var out bytes.Buffer
+	UnifiedDiff(&out, "a/f", "b/f", []byte(strings.Join(old, "\n")+"\n"), []byte(strings.Join(changed, "\n")+"\n"))
+	if hunks := strings.Count(out.String(), "\n@@ "); hunks != 2 {
+		t.Errorf("got %d hunks, want 2:\n%s", hunks, out.String())
+	}
+}
+
+func TestSplitLines(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"", nil},
+		{"a", []string{"a"}},
+		{"a\n", []string{"a\n"}},
+		{"a\n\nb", []string{"a\n", "\n", "b"}},
+		{"a\r\nb\r\n", []string{"a\r\n", "b\r\n"}},
+	}
+	for _, test := range tests {
+		got := splitLines(test.text)
+		if strings.Join(got, "|") != strings.Join(test.want, "|") || len(got) != len(test.want) {
+			t.Errorf("splitLines(%q) = %q, want %q", test.text, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,322 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// SourceFile is a file selected for flattening
+type SourceFile struct {
+	// Path is the slash-separated path shown in the output, relative to its root.
+	// With several roots it starts with the root's directory name.
+	Path    string
+	AbsPath string
+	Size    int64
+	Content []byte
+}
+
+// SkipReason tells why a file was left out of the output
+type SkipReason string
+
+// Reasons reported for skipped files. Files excluded by ignore files or globs are not reported.
+const (
+	SkipExtension    SkipReason = "excluded extension"
+	SkipLockFile     SkipReason = "lock file"
+	SkipDependency   SkipReason = "dependency directory"
+	SkipMinified     SkipReason = "minified or bundled"
+	SkipBinary       SkipReason = "binary content"
+	SkipFileSize     SkipReason = "larger than -max-size"
+	SkipTotalSize    SkipReason = "over -max-total"
+	SkipSymlink      SkipReason = "broken symlink"
+	SkipCommentedOut SkipReason = "commented-out Go file"
+	SkipNoExports    SkipReason = "no exported declarations"
+)
+
+// SkippedFile is a file or directory left out of the output
+type SkippedFile struct {
+	Path   string
+	Reason SkipReason
+	Detail string
+	Size   int64
+}
+
+// Flattener walks one or more roots and selects the files to combine
+type Flattener struct {
+	Roots []string
+
+	// Include limits the files to those matching one of the globs; empty includes every file
+	Include []*Pattern
+
+	// Exclude skips the files and directories matching one of the globs
+	Exclude []*Pattern
+
+	// MaxFileSize skips larger files; zero means no limit
+	MaxFileSize int64
+
+	// MaxTotalSize skips the files that would take the output over this size; zero means no limit
+	MaxTotalSize int64
+
+	// FollowSymlinks walks into linked directories and reads linked files
+	FollowSymlinks bool
+
+	// UseIgnoreFiles applies the patterns of .gitignore and .ignore files
+	UseIgnoreFiles bool
+
+	// GoSummary summarizes .go files and may order them by package imports; nil leaves them as they are
+	GoSummary *GoSummarizer
+
+	// Secrets redacts the secrets it finds in every selected file; nil leaves files as they are
+	Secrets *SecretScanner
+
+	// Skipped lists the files left out by the last Walk, in walk order
+	Skipped []SkippedFile
+
+	// TotalSize is the number of content bytes passed on by the last Walk
+	TotalSize int64
+
+	skipPaths map[string]bool
+}
+
+// NewFlattener creates a flattener for the roots that respects ignore files
+func NewFlattener(roots []string) *Flattener {
+	return &Flattener{
+		Roots:          roots,
+		UseIgnoreFiles: true,
+		skipPaths:      make(map[string]bool),
+	}
+}
+
+// Skip never flattens the file at path, such as the output file itself
+func (f *Flattener) Skip(filePath string) {
+	if abs, err := filepath.Abs(filePath); err == nil {
+		f.skipPaths[abs] = true
+	}
+}
+
+// Walk calls visit for every selected file, root by root in name order
+func (f *Flattener) Walk(visit func(SourceFile) error) error {
+	f.Skipped = nil
+	f.TotalSize = 0
+
+	// Ordering by imports needs every file before the first is passed on
+	if f.GoSummary != nil && f.GoSummary.OrderByImports {
+		files := []SourceFile{}
+		collect := func(file SourceFile) error {
+			files = append(files, file)
+			return nil
+		}
+		if err := f.walkRoots(collect); err != nil {
+			return err
+		}
+		for _, file := range f.GoSummary.Order(files) {
+			if err := visit(file); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return f.walkRoots(visit)
+}
+
+// walkRoots walks every root in turn. With several roots each one is named by its
+// base name, numbered ("pkg_2") when an earlier root already took it.
+func (f *Flattener) walkRoots(visit func(SourceFile) error) error {
+	names := map[string]bool{}
+	for _, root := range f.Roots {
+		abs, err := filepath.Abs(root)
+		if err != nil {
+			return fmt.Errorf("error resolving root %s: %v", root, err)
+		}
+		info, err := os.Stat(abs)
+		if err != nil {
+			return fmt.Errorf("error reading root %s: %v", root, err)
+		}
+
+		if !info.IsDir() {
+			if err := f.visitFile(abs, uniqueRootName(names, info.Name(), filepath.Ext(info.Name())), info, visit); err != nil {
+				return err
+			}
+			continue
+		}
+
+		prefix := ""
+		if len(f.Roots) > 1 {
+			prefix = uniqueRootName(names, filepath.Base(abs), "")
+		}
+		if err := f.walkDir(abs, "", prefix, IgnoreRules{}, map[string]bool{}, visit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// uniqueRootName returns the name, numbered before the extension when it is already taken
+func uniqueRootName(names map[string]bool, name, ext string) string {
+	unique := name
+	for n := 2; names[unique]; n++ {
+		unique = fmt.Sprintf("%s_%d%s", strings.TrimSuffix(name, ext), n, ext)
+	}
+	names[unique] = true
+	return unique
+}
+
+// walkDir visits the files of one directory and recurses into its subdirectories.
+// visited holds the resolved directories already walked, so symlink loops end.
+func (f *Flattener) walkDir(dir, relDir, prefix string, rules IgnoreRules, visited map[string]bool, visit func(SourceFile) error) error {
+	real, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return fmt.Errorf("error resolving %s: %v", dir, err)
+	}
+	if visited[real] {
+		return nil
+	}
+	visited[real] = true
+
+	if f.UseIgnoreFiles {
+		if rules, err = rules.Load(dir, relDir); err != nil {
+			return err
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("error reading directory %s: %v", dir, err)
+	}
+
+	for _, entry := range entries {
+		// Skip hidden files and directories, including .git
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		fullPath := filepath.Join(dir, entry.Name())
+		relPath := path.Join(relDir, entry.Name())
+
+		info, err := entry.Info()
+		if err != nil {
+			return fmt.Errorf("error reading %s: %v", fullPath, err)
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			if !f.FollowSymlinks {
+				continue
+			}
+			if info, err = os.Stat(fullPath); err != nil {
+				f.skip(path.Join(prefix, relPath), SkipSymlink, err.Error(), 0)
+				continue
+			}
+		}
+
+		isDir := info.IsDir()
+		if f.UseIgnoreFiles && rules.Ignored(relPath, isDir) {
+			continue
+		}
+		if MatchAny(f.Exclude, relPath, isDir) {
+			continue
+		}
+
+		if isDir {
+			if dependencyDirs[entry.Name()] {
+				f.skip(path.Join(prefix, relPath)+"/", SkipDependency, "", 0)
+				continue
+			}
+			if err := f.walkDir(fullPath, relPath, prefix, rules, visited, visit); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if len(f.Include) > 0 && !MatchAny(f.Include, relPath, false) {
+			continue
+		}
+		if err := f.visitFile(fullPath, path.Join(prefix, relPath), info, visit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// visitFile reads a selected file and passes it on, unless it is skipped by
+// name, size or content. Cheap checks on the name and size come first.
+func (f *Flattener) visitFile(fullPath, displayPath string, info os.FileInfo, visit func(SourceFile) error) error {
+	if !info.Mode().IsRegular() || f.skipPaths[fullPath] {
+		return nil
+	}
+
+	name, size := info.Name(), info.Size()
+	switch {
+	case isExcludedFile(name):
+		f.skip(displayPath, SkipExtension, "", size)
+		return nil
+	case lockFileNames[name]:
+		f.skip(displayPath, SkipLockFile, "", size)
+		return nil
+	case f.MaxFileSize > 0 && size > f.MaxFileSize:
+		f.skip(displayPath, SkipFileSize, "", size)
+		return nil
+	case f.MaxTotalSize > 0 && f.TotalSize+size > f.MaxTotalSize:
+		f.skip(displayPath, SkipTotalSize, "", size)
+		return nil
+	}
+
+	content, err := os.ReadFile(fullPath)
+	if err != nil {
+		return fmt.Errorf("error reading file %s: %v", fullPath, err)
+	}
+
+	if detail := detectBinary(content); detail != "" {
+		f.skip(displayPath, SkipBinary, detail, size)
+		return nil
+	}
+	if isMinified(name, content) {
+		f.skip(displayPath, SkipMinified, "", size)
+		return nil
+	}
+
+	if f.GoSummary != nil && strings.HasSuffix(name, ".go") {
+		var reason SkipReason
+		if content, reason = f.GoSummary.Summarize(SourceFile{Path: displayPath, AbsPath: fullPath, Content: content}); reason != "" {
+			f.skip(displayPath, reason, "", size)
+			return nil
+		}
+	}
+	if f.Secrets != nil {
+		content = f.Secrets.Scan(displayPath, content)
+	}
+
+	f.TotalSize += int64(len(content))
+	return visit(SourceFile{
+		Path:    displayPath,
+		AbsPath: fullPath,
+		Size:    info.Size(),
+		Content: content,
+	})
+}
+
+// skip records a file left out of the output
+func (f *Flattener) skip(displayPath string, reason SkipReason, detail string, size int64) {
+	f.Skipped = append(f.Skipped, SkippedFile{Path: displayPath, Reason: reason, Detail: detail, Size: size})
+}
+
+// isExcludedFile is a fast path for well-known binary types; anything else is sniffed
+func isExcludedFile(filename string) bool {
+	// Add file extensions or patterns you want to exclude
+	excludedExtensions := []string{
+		".exe", ".dll", ".so", ".dylib", ".o", ".a", ".class", ".jar", ".pyc", ".wasm",
+		".zip", ".tar", ".gz", ".tgz", ".bz2", ".xz", ".7z", ".rar",
+		".jpg", ".jpeg", ".png", ".gif", ".bmp", ".ico", ".webp", ".tiff",
+		".woff", ".woff2", ".ttf", ".otf", ".eot",
+		".mp3", ".mp4", ".wav", ".ogg", ".mov", ".avi",
+		".sqlite", ".sqlite3", ".db",
+		".pdf", ".doc", ".docx", ".xls", ".xlsx", ".ppt", ".pptx",
+	}
+
+	for _, ext := range excludedExtensions {
+		if strings.HasSuffix(filename, ext) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,554 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/scanner"
+	"go/token"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GoSummarizer shrinks .go files with go/ast before they are written
+type GoSummarizer struct {
+	// StripComments removes blocks of commented-out code and files that are nothing else
+	StripComments bool
+
+	// ExportedOnly keeps the exported declarations, with signatures instead of function bodies
+	ExportedOnly bool
+
+	// OrderByImports writes the Go packages so that every package comes after the ones it imports
+	OrderByImports bool
+
+	// StrippedBlocks counts the commented-out blocks removed by Summarize
+	StrippedBlocks int
+
+	// OriginalSize and SummarySize are the .go bytes before and after Summarize
+	OriginalSize int64
+	SummarySize  int64
+
+	packages map[string]*goPackage
+	modules  map[string]goModule
+}
+
+// goPackage is a directory of Go files seen by Summarize
+type goPackage struct {
+	importPath string
+	imports    map[string]bool
+}
+
+// goModule is the module a directory belongs to
+type goModule struct {
+	dir  string
+	path string
+}
+
+// Go summary options for -go-summary
+const (
+	GoStrip    = "strip"
+	GoExported = "exported"
+	GoOrder    = "order"
+)
+
+// ParseGoSummary creates a summarizer from a comma-separated list of options, or "all"
+func ParseGoSummary(value string) (*GoSummarizer, error) {
+	gs := NewGoSummarizer()
+	for _, option := range strings.Split(value, ",") {
+		switch strings.ToLower(strings.TrimSpace(option)) {
+		case GoStrip:
+			gs.StripComments = true
+		case GoExported:
+			gs.ExportedOnly = true
+		case GoOrder:
+			gs.OrderByImports = true
+		case "all":
+			gs.StripComments, gs.ExportedOnly, gs.OrderByImports = true, true, true
+		default:
+			return nil, fmt.Errorf("unknown Go summary option '%s', want %s, %s, %s or all", option, GoStrip, GoExported, GoOrder)
+		}
+	}
+	return gs, nil
+}
+
+// NewGoSummarizer creates a summarizer with every option off
+func NewGoSummarizer() *GoSummarizer {
+	return &GoSummarizer{
+		packages: make(map[string]*goPackage),
+		modules:  make(map[string]goModule),
+	}
+}
+
+// Summarize returns the summarized contents of a .go file, or a reason to leave it
+// out. Files that don't parse are passed on as they are.
+func (gs *GoSummarizer) Summarize(file SourceFile) ([]byte, SkipReason) {
+	gs.OriginalSize += int64(len(file.Content))
+	content, reason := gs.summarize(file)
+	if reason == "" {
+		gs.SummarySize += int64(len(content))
+	}
+	return content, reason
+}
+
+func (gs *GoSummarizer) summarize(file SourceFile) ([]byte, SkipReason) {
+	// Ordering alone leaves every file as it is
+	if (gs.StripComments || gs.ExportedOnly) && isCommentedOutFile(file.Content) {
+		return nil, SkipCommentedOut
+	}
+
+	fset := token.NewFileSet()
+	parsed, err := parser.ParseFile(fset, file.Path, file.Content, parser.ParseComments)
+	if err != nil {
+		return file.Content, ""
+	}
+	if gs.OrderByImports {
+		gs.recordImports(file.AbsPath, parsed)
+	}
+
+	switch {
+	case gs.ExportedOnly:
+		content, ok := exportedSummary(fset, parsed)
+		if !ok {
+			return nil, SkipNoExports
+		}
+		return content, ""
+	case gs.StripComments:
+		content, stripped := stripCommentedCode(fset, parsed, file.Content)
+		gs.StrippedBlocks += stripped
+		return content, ""
+	}
+	return file.Content, ""
+}
+
+// isCommentedOutFile reports whether a file holds nothing but comments and
+// maybe a package clause. A package doc comment, as in doc.go, keeps the file.
+func isCommentedOutFile(content []byte) bool {
+	fset := token.NewFileSet()
+	if parsed, err := parser.ParseFile(fset, "", content, parser.ParseComments|parser.PackageClauseOnly); err == nil && parsed.Doc != nil {
+		return false
+	}
+
+	var s scanner.Scanner
+	s.Init(fset.AddFile("", fset.Base(), len(content)), content, nil, 0)
+	tokens := []token.Token{}
+	for {
+		_, tok, _ := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		if tok != token.SEMICOLON {
+			tokens = append(tokens, tok)
+		}
+	}
+	return len(tokens) == 0 || (len(tokens) == 2 && tokens[0] == token.PACKAGE && tokens[1] == token.IDENT)
+}
+
+// attachedComments returns the doc and line comments of the file's declarations,
+// as opposed to the floating comments between them
+func attachedComments(node ast.Node) map[*ast.CommentGroup]bool {
+	attached := map[*ast.CommentGroup]bool{}
+	ast.Inspect(node, func(n ast.Node) bool {
+		if group, ok := n.(*ast.CommentGroup); ok {
+			attached[group] = true
+		}
+		return true
+	})
+	return attached
+}
+
+// stripCommentedCode removes the floating comments that are commented-out code.
+// Consecutive comments separated only by blank lines are tried together, since
+// commented-out code is often split by them.
+func stripCommentedCode(fset *token.FileSet, file *ast.File, content []byte) ([]byte, int) {
+	attached := attachedComments(file)
+	runs := [][]*ast.CommentGroup{}
+	lastEnd := -1
+	for _, group := range file.Comments {
+		if attached[group] {
+			lastEnd = -1
+			continue
+		}
+		start, end := fset.Position(group.Pos()).Offset, fset.Position(group.End()).Offset
+		if lastEnd >= 0 && len(bytes.TrimSpace(content[lastEnd:start])) == 0 {
+			runs[len(runs)-1] = append(runs[len(runs)-1], group)
+		} else {
+			runs = append(runs, []*ast.CommentGroup{group})
+		}
+		lastEnd = end
+	}
+
+	// Find the longest stretch of each run that parses as code, starting from every group
+	type span struct{ start, end int }
+	removed := []span{}
+	stripped := 0
+	for _, run := range runs {
+		for i := 0; i < len(run); {
+			j := len(run) - 1
+			for ; j >= i && !isCommentedCode(run[i:j+1]); j-- {
+			}
+			if j < i {
+				i++
+				continue
+			}
+
+			start, end := fset.Position(run[i].Pos()).Offset, fset.Position(run[j].End()).Offset
+			lineStart := bytes.LastIndexByte(content[:start], '\n') + 1
+			lineEnd := bytes.IndexByte(content[end:], '\n')
+			if lineEnd < 0 {
+				lineEnd = len(content)
+			} else {
+				lineEnd += end + 1
+			}
+			// Only whole lines go, so a comment after code on the same line stays
+			if len(bytes.TrimSpace(content[lineStart:start])) == 0 && len(bytes.TrimSpace(content[end:lineEnd])) == 0 {
+				// Don't leave two blank lines where the block was
+				if lineStart == 0 || bytes.HasSuffix(content[:lineStart], []byte("\n\n")) {
+					for lineEnd < len(content) && content[lineEnd] == '\n' {
+						lineEnd++
+					}
+				}
+				removed = append(removed, span{lineStart, lineEnd})
+				stripped++
+			}
+			i = j + 1
+		}
+	}
+	if stripped == 0 {
+		return content, 0
+	}
+
+	var out bytes.Buffer
+	last := 0
+	for _, s := range removed {
+		out.Write(content[last:s.start])
+		last = s.end
+	}
+	out.Write(content[last:])
+	return out.Bytes(), stripped
+}
+
+// isCommentedCode reports whether the text of the comments parses as Go
+// declarations or statements, rather than prose
+func isCommentedCode(groups []*ast.CommentGroup) bool {
+	var text strings.Builder
+	lines := 0
+	for _, group := range groups {
+		for _, comment := range group.List {
+			body := comment.Text
+			if strings.HasPrefix(body, "//") {
+				body = strings.TrimPrefix(strings.TrimPrefix(body, "//"), " ")
+			} else {
+				body = strings.TrimSuffix(strings.TrimPrefix(body, "/*"), "*/")
+			}
+			text.WriteString(body + "\n")
+			lines += strings.Count(body, "\n") + 1
+		}
+		text.WriteString("\n")
+	}
+	if lines < 2 {
+		return false
+	}
+
+	fset := token.NewFileSet()
+	if file, err := parser.ParseFile(fset, "", "package p\n"+text.String(), 0); err == nil {
+		return len(file.Decls) > 0
+	}
+	file, err := parser.ParseFile(fset, "", "package p\nfunc _() {\n"+text.String()+"\n}", 0)
+	if err != nil {
+		return false
+	}
+	stmts := file.Decls[0].(*ast.FuncDecl).Body.List
+	for _, stmt := range stmts {
+		if !isCodeStmt(stmt) {
+			return false
+		}
+	}
+	return len(stmts) > 0
+}
+
+// isCodeStmt rejects the statements that prose also parses as, such as a lone
+// word or "TODO: fix"
+func isCodeStmt(stmt ast.Stmt) bool {
+	switch s := stmt.(type) {
+	case *ast.ExprStmt:
+		_, isCall := s.X.(*ast.CallExpr)
+		return isCall
+	case *ast.LabeledStmt:
+		return isCodeStmt(s.Stmt)
+	case *ast.EmptyStmt:
+		return false
+	}
+	return true
+}
+
+// exportedSummary prints the package clause, the imports still in use and the
+// exported declarations with their doc comments. Function bodies are dropped and
+// composite or function literal values are elided. It reports false when
+// nothing is exported.
+func exportedSummary(fset *token.FileSet, file *ast.File) ([]byte, bool) {
+	// FileExports drops the imports, so keep a copy
+	imports := []*ast.GenDecl{}
+	for _, decl := range file.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.IMPORT {
+			copied := *gen
+			imports = append(imports, &copied)
+		}
+	}
+
+	ast.FileExports(file)
+	decls := []ast.Decl{}
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok {
+			if fn.Recv != nil && !ast.IsExported(receiverName(fn.Recv)) {
+				continue
+			}
+			fn.Body = nil
+		}
+		decls = append(decls, decl)
+	}
+	if len(decls) == 0 {
+		return nil, false
+	}
+
+	ast.Inspect(&ast.File{Decls: decls}, func(n ast.Node) bool {
+		switch lit := n.(type) {
+		case *ast.CompositeLit:
+			if len(lit.Elts) > 0 {
+				lit.Elts = []ast.Expr{&ast.Ident{Name: "...", NamePos: lit.Lbrace}}
+				lit.Rbrace = lit.Lbrace
+			}
+			return false
+		case *ast.FuncLit:
+			lit.Body = &ast.BlockStmt{
+				Lbrace: lit.Body.Lbrace,
+				List:   []ast.Stmt{&ast.ExprStmt{X: &ast.Ident{Name: "...", NamePos: lit.Body.Lbrace}}},
+				Rbrace: lit.Body.Lbrace,
+			}
+			return false
+		}
+		return true
+	})
+
+	used := usedPackageNames(decls)
+	for i := len(imports) - 1; i >= 0; i-- {
+		specs := []ast.Spec{}
+		for _, spec := range imports[i].Specs {
+			if used[importName(spec.(*ast.ImportSpec))] {
+				specs = append(specs, spec)
+			}
+		}
+		if len(specs) > 0 {
+			imports[i].Specs = specs
+			imports[i].Doc = nil
+			decls = append([]ast.Decl{imports[i]}, decls...)
+		}
+	}
+
+	file.Decls = decls
+	file.Comments = nil
+	for group := range attachedComments(file) {
+		file.Comments = append(file.Comments, group)
+	}
+	sort.Slice(file.Comments, func(i, j int) bool {
+		return file.Comments[i].Pos() < file.Comments[j].Pos()
+	})
+
+	var out bytes.Buffer
+	// The printer, not format.Node, since "..." doesn't parse back
+	config := printer.Config{Mode: printer.UseSpaces | printer.TabIndent, Tabwidth: 8}
+	if err := config.Fprint(&out, fset, file); err != nil {
+		return nil, false
+	}
+	// Removed fields leave blank lines before the closing brace
+	return closingBlankLines.ReplaceAll(out.Bytes(), []byte("\n$1")), true
+}
+
+// closingBlankLines matches blank lines before a closing brace or parenthesis
+var closingBlankLines = regexp.MustCompile(`\n(?:[ \t]*\n)+([ \t]*[})]\n)`)
+
+// receiverName returns the type name of a method receiver, without pointer or type parameters
+func receiverName(recv *ast.FieldList) string {
+	if len(recv.List) == 0 {
+		return ""
+	}
+	expr := recv.List[0].Type
+	for {
+		switch t := expr.(type) {
+		case *ast.StarExpr:
+			expr = t.X
+		case *ast.IndexExpr:
+			expr = t.X
+		case *ast.IndexListExpr:
+			expr = t.X
+		case *ast.Ident:
+			return t.Name
+		default:
+			return ""
+		}
+	}
+}
+
+// usedPackageNames collects the identifiers used as package qualifiers, such as io in io.Writer
+func usedPackageNames(decls []ast.Decl) map[string]bool {
+	used := map[string]bool{}
+	for _, decl := range decls {
+		ast.Inspect(decl, func(n ast.Node) bool {
+			if sel, ok := n.(*ast.SelectorExpr); ok {
+				if ident, ok := sel.X.(*ast.Ident); ok {
+					used[ident.Name] = true
+				}
+			}
+			return true
+		})
+	}
+	return used
+}
+
+// majorVersion matches the version element at the end of an import path, such as /v2 or .v3
+var majorVersion = regexp.MustCompile(`[./]v[0-9]+$`)
+
+// importName guesses the name an import is used by: its alias, or the last path
+// element without a major version
+func importName(spec *ast.ImportSpec) string {
+	if spec.Name != nil {
+		return spec.Name.Name
+	}
+	importPath, _ := strconv.Unquote(spec.Path.Value)
+	importPath = majorVersion.ReplaceAllString(importPath, "")
+	name := path.Base(importPath)
+	name = strings.TrimPrefix(name, "go-")
+	return strings.ReplaceAll(name, "-", "")
+}
+
+// recordImports remembers the import path of the file's package and the packages it imports
+func (gs *GoSummarizer) recordImports(absPath string, file *ast.File) {
+	dir := filepath.Dir(absPath)
+	pkg := gs.packages[dir]
+	if pkg == nil {
+		pkg = &goPackage{importPath: gs.importPath(dir), imports: map[string]bool{}}
+		gs.packages[dir] = pkg
+	}
+	for _, spec := range file.Imports {
+		if importPath, err := strconv.Unquote(spec.Path.Value); err == nil {
+			pkg.imports[importPath] = true
+		}
+	}
+}
+
+// importPath returns the import path of the package in dir from the nearest go.mod, or ""
+func (gs *GoSummarizer) importPath(dir string) string {
+	module := gs.findModule(dir)
+	if module.path == "" {
+		return ""
+	}
+	rel, err := filepath.Rel(module.dir, dir)
+	if err != nil || rel == "." {
+		return module.path
+	}
+	return module.path + "/" + filepath.ToSlash(rel)
+}
+
+// findModule looks for the go.mod of dir or one of its parents
+func (gs *GoSummarizer) findModule(dir string) goModule {
+	if module, ok := gs.modules[dir]; ok {
+		return module
+	}
+
+	module := goModule{}
+	if modulePath := readModulePath(filepath.Join(dir, "go.mod")); modulePath != "" {
+		module = goModule{dir: dir, path: modulePath}
+	} else if parent := filepath.Dir(dir); parent != dir {
+		module = gs.findModule(parent)
+	}
+	gs.modules[dir] = module
+	return module
+}
+
+// readModulePath returns the module path declared in a go.mod file, or "" when there is none
+func readModulePath(goModPath string) string {
+	file, err := os.Open(goModPath)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "module" {
+			modulePath, err := strconv.Unquote(fields[1])
+			if err != nil {
+				modulePath = fields[1]
+			}
+			return modulePath
+		}
+	}
+	return ""
+}
+
+// Order puts the files that are not Go first, in walk order, then the Go packages
+// so that each comes after the packages it imports. Packages that don't depend on
+// each other keep their walk order.
+func (gs *GoSummarizer) Order(files []SourceFile) []SourceFile {
+	ordered := []SourceFile{}
+	byDir := map[string][]SourceFile{}
+	dirs := []string{}
+	for _, file := range files {
+		if !strings.HasSuffix(file.Path, ".go") {
+			ordered = append(ordered, file)
+			continue
+		}
+		dir := filepath.Dir(file.AbsPath)
+		if byDir[dir] == nil {
+			dirs = append(dirs, dir)
+		}
+		byDir[dir] = append(byDir[dir], file)
+	}
+
+	// Depth-first, so a package is placed once everything it imports is
+	placed := map[string]bool{}
+	visiting := map[string]bool{}
+	var place func(dir string)
+	place = func(dir string) {
+		if placed[dir] || visiting[dir] {
+			return
+		}
+		visiting[dir] = true
+		if pkg := gs.packages[dir]; pkg != nil {
+			for _, dep := range dirs {
+				if depPkg := gs.packages[dep]; depPkg != nil && depPkg.importPath != "" && pkg.imports[depPkg.importPath] {
+					place(dep)
+				}
+			}
+		}
+		visiting[dir] = false
+		placed[dir] = true
+		ordered = append(ordered, byDir[dir]...)
+	}
+	for _, dir := range dirs {
+		place(dir)
+	}
+	return ordered
+}
+
+// printGoSummary prints how much the Go files shrank
+func printGoSummary(w io.Writer, gs *GoSummarizer) {
+	if gs == nil || gs.OriginalSize == 0 {
+		return
+	}
+	fmt.Fprintf(w, "=== Go Summary ===\n")
+	fmt.Fprintf(w, "Go files: %s summarized to %s\n", formatSize(gs.OriginalSize), formatSize(gs.SummarySize))
+	if gs.StrippedBlocks > 0 {
+		fmt.Fprintf(w, "Commented-out blocks stripped: %d\n", gs.StrippedBlocks)
+	}
+	fmt.Fprintf(w, "\n")
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestIsCommentedCode(t *testing.T) {
+	tests := []struct {
+		name    string
+		comment string
+		want    bool
+	}{
+		{"declaration", "// func old() {\n//\treturn\n// }", true},
+		{"statements", "// x := load()\n// x.Save()", true},
+		{"block comment", "/*\nif err != nil {\n\treturn err\n}\n*/", true},
+		{"import", "// import \"fmt\"\n// var _ = fmt.Println", true},
+
+		// Prose parses as lone words or labels, which doesn't count
+		{"prose", "// This is a comment\n// about the code below.", false},
+		{"todo", "// TODO: fix\n// later", false},
+		{"words", "// first\n// second", false},
+		{"single line", "// x := load()", false},
+		{"not go", "// Use it like this:\n//   wordfinder -hints abandon", false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fset := token.NewFileSet()
+			file, err := parser.ParseFile(fset, "", "package p\n\n"+test.comment+"\n", parser.ParseComments)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := isCommentedCode(file.Comments); got != test.want {
+				t.Errorf("isCommentedCode = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestStripCommentedCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		want     string
+		stripped int
+	}{
+		{
+			"block between functions",
+			"package p\n\nfunc a() {}\n\n// func b() {\n// \treturn\n// }\n\nfunc c() {}\n",
+			"package p\n\nfunc a() {}\n\nfunc c() {}\n",
+			1,
+		},
+		{
+			"block split by a blank line",
+			"package p\n\n// x := 1\n// y := 2\n\n// z := x + y\n// use(z)\n\nfunc c() {}\n",
+			"package p\n\nfunc c() {}\n",
+			1,
+		},
+		{
+			"prose and doc comments stay",
+			"package p\n\n// Notes about the\n// package as a whole.\n\n// c does nothing\n// at all.\nfunc c() {}\n",
+			"package p\n\n// Notes about the\n// package as a whole.\n\n// c does nothing\n// at all.\nfunc c() {}\n",
+			0,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fset := token.NewFileSet()
+			file, err := parser.ParseFile(fset, "", test.content, parser.ParseComments)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, stripped := stripCommentedCode(fset, file, []byte(test.content))
+			if string(got) != test.want || stripped != test.stripped {
+				t.Errorf("got %d blocks stripped and\n%s\nwant %d and\n%s", stripped, got, test.stripped, test.want)
+			}
+		})
+	}
+}
+
+func TestSummarizeCommentedOutFile(t *testing.T) {
+	commentedOut := "package p\n\n// func old() {\n// \treturn\n// }\n"
+	tests := []struct {
+		name    string
+		options string
+		content string
+		skipped bool
+	}{
+		{"strip", GoStrip, commentedOut, true},
+		{"exported", GoExported, commentedOut, true},
+		{"all", "all", "// package p\n\n// func old() {}\n", true},
+
+		// Ordering alone leaves every file in
+		{"order", GoOrder, commentedOut, false},
+
+		// A package doc comment keeps the file
+		{"doc.go", GoStrip, "// Package p does things.\npackage p\n", false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			gs, err := ParseGoSummary(test.options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, reason := gs.Summarize(SourceFile{Path: "p/old.go", AbsPath: "/src/p/old.go", Content: []byte(test.content)})
+			if skipped := reason == SkipCommentedOut; skipped != test.skipped {
+				t.Errorf("skip reason %q, want skipped %v", reason, test.skipped)
+			}
+		})
+	}
+}
+
+func TestExportedSummary(t *testing.T) {
+	content := `package p
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	yaml "gopkg.in/yaml.v3"
+)
+
+// Config is exported
+type Config struct {
+	Name   string
+	hidden int
+}
+
+// Load reads a config
+func Load(r io.Reader) (*Config, error) {
+	data, _ := io.ReadAll(r)
+	return parse(strings.TrimSpace(string(data)))
+}
+
+// Defaults lists the default configs
+var Defaults = []Config{{Name: "a"}, {Name: "b"}}
+
+// Marshal encodes a config
+func (c *Config) Marshal() ([]byte, error) {
+	return yaml.Marshal(c)
+}
+
+func parse(text string) (*Config, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+`
+	want := `package p
+
+import (
+	"io"
+)
+
+// Config is exported
+type Config struct {
+	Name string
+}
+
+// Load reads a config
+func Load(r io.Reader) (*Config, error)
+
+// Defaults lists the default configs
+var Defaults = []Config{...}
+
+// Marshal encodes a config
+func (c *Config) Marshal() ([]byte, error)
+`
+	gs := NewGoSummarizer()
+	gs.ExportedOnly = true
+	got, reason := gs.Summarize(SourceFile{Path: "p/p.go", Content: []byte(content)})
+	if reason != "" {
+		t.Fatalf("skipped: %s", reason)
+	}
+	if string(got) != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+
+	if _, reason := gs.Summarize(SourceFile{Path: "p/q.go", Content: []byte("package p\n\nfunc hidden() {}\n")}); reason != SkipNoExports {
+		t.Errorf("file without exports: skip reason %q, want %q", reason, SkipNoExports)
+	}
+}
+
+func TestImportName(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{`"fmt"`, "fmt"},
+		{`"net/http"`, "http"},
+		{`"gopkg.in/yaml.v3"`, "yaml"},
+		{`"github.com/go-chi/chi/v5"`, "chi"},
+		{`"github.com/mattn/go-isatty"`, "isatty"},
+		{`alias "example.com/pkg"`, "alias"},
+	}
+	for _, test := range tests {
+		file, err := parser.ParseFile(token.NewFileSet(), "", "package p\nimport "+test.path+"\n", 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := importName(file.Imports[0]); got != test.want {
+			t.Errorf("importName(%s) = %q, want %q", test.path, got, test.want)
+		}
+	}
+}
+
+func TestOrderByImports(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"go.mod":          "module example.com/app\n\ngo 1.23\n",
+		"README.md":       "# app\n",
+		"main.go":         "package main\n\nimport \"example.com/app/store\"\n\nfunc main() { store.Open() }\n",
+		"api/api.go":      "package api\n\nimport (\n\t\"fmt\"\n\t\"example.com/app/store\"\n)\n\nvar _ = fmt.Sprint(store.Open)\n",
+		"store/store.go":  "package store\n\nimport \"example.com/app/model\"\n\nfunc Open() model.Item { return model.Item{} }\n",
+		"store/cache.go":  "package store\n",
+		"model/model.go":  "package model\n\ntype Item struct{}\n",
+		"tools/tools.go":  "package tools\n",
+		"other/go.mod":    "module example.com/other\n",
+		"other/other.go":  "package other\n\nimport \"example.com/app/model\"\n",
+		"other/sub/x.go":  "package sub\n\nimport \"example.com/other\"\n",
+		"broken/bad.go":   "package broken\n\nfunc {\n",
+		"store/notes.txt": "notes\n",
+	}
+	walkOrder := []string{"README.md", "main.go", "api/api.go", "store/store.go", "store/cache.go", "store/notes.txt", "model/model.go", "tools/tools.go", "other/sub/x.go", "other/other.go", "broken/bad.go"}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	gs := NewGoSummarizer()
+	gs.OrderByImports = true
+	sourceFiles := []SourceFile{}
+	for _, name := range walkOrder {
+		file := SourceFile{Path: name, AbsPath: filepath.Join(root, name), Content: []byte(files[name])}
+		if strings.HasSuffix(name, ".go") {
+			gs.Summarize(file)
+		}
+		sourceFiles = append(sourceFiles, file)
+	}
+
+	// Packages follow what they import, across both modules; the rest keeps walk order
+	want := "README.md store/notes.txt model/model.go store/store.go store/cache.go main.go api/api.go tools/tools.go other/other.go other/sub/x.go broken/bad.go"
+	paths := []string{}
+	for _, file := range gs.Order(sourceFiles) {
+		paths = append(paths, file.Path)
+	}
+	if got := strings.Join(paths, " "); got != want {
+		t.Errorf("order\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ignoreFileNames are the files whose patterns exclude paths in their directory and below
+var ignoreFileNames = []string{".gitignore", ".ignore"}
+
+// Pattern is a gitignore-style glob. Without a slash it matches a name at any
+// depth; with one it is anchored to its base directory. "**" matches any
+// number of directories.
+type Pattern struct {
+	text    string
+	base    string
+	regex   *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+// ParsePattern compiles a glob relative to base, a slash-separated directory ("" for the root)
+func ParsePattern(glob, base string) (*Pattern, error) {
+	p := &Pattern{text: glob, base: base}
+
+	if strings.HasPrefix(glob, "!") {
+		p.negate = true
+		glob = glob[1:]
+	} else if strings.HasPrefix(glob, `\!`) || strings.HasPrefix(glob, `\#`) {
+		glob = glob[1:]
+	}
+	if strings.HasSuffix(glob, "/") {
+		p.dirOnly = true
+		glob = strings.TrimRight(glob, "/")
+	}
+	if glob == "" {
+		return nil, fmt.Errorf("empty pattern '%s'", p.text)
+	}
+
+	anchored := strings.Contains(glob, "/")
+	glob = strings.TrimPrefix(glob, "/")
+
+	expr := globToRegexp(glob)
+	if anchored {
+		expr = "^" + expr + "$"
+	} else {
+		expr = "^(?:.*/)?" + expr + "$"
+	}
+
+	regex, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern '%s': %v", p.text, err)
+	}
+	p.regex = regex
+	return p, nil
+}
+
+// Match reports whether the slash-separated path, relative to the root, matches the pattern
+func (p *Pattern) Match(relPath string, isDir bool) bool {
+	if p.dirOnly && !isDir {
+		return false
+	}
+	if p.base != "" {
+		if !strings.HasPrefix(relPath, p.base+"/") {
+			return false
+		}
+		relPath = strings.TrimPrefix(relPath, p.base+"/")
+	}
+	return p.regex.MatchString(relPath)
+}
+
+// String returns the pattern as written
+func (p *Pattern) String() string {
+	return p.text
+}
+
+// globToRegexp translates glob syntax into a regular expression
+func globToRegexp(glob string) string {
+	var expr strings.Builder
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case strings.HasPrefix(glob[i:], "**/"):
+			expr.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "/**") && i+3 == len(glob):
+			expr.WriteString("/.*")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			expr.WriteString(".*")
+			i++
+		case c == '*':
+			expr.WriteString("[^/]*")
+		case c == '?':
+			expr.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				expr.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			expr.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i += end + 1
+		case c == '\\' && i+1 < len(glob):
+			i++
+			expr.WriteString(regexp.QuoteMeta(string(glob[i])))
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return expr.String()
+}
+
+// IgnoreRules are the patterns of the ignore files found on the way down to a directory.
+// Later rules win, so a deeper file can re-include what a parent excluded.
+type IgnoreRules []*Pattern
+
+// Load returns the rules extended with the ignore files of the directory at dirPath,
+// whose slash-separated path relative to the root is relDir
+func (ir IgnoreRules) Load(dirPath, relDir string) (IgnoreRules, error) {
+	rules := ir
+	for _, name := range ignoreFileNames {
+		patterns, err := readIgnoreFile(filepath.Join(dirPath, name), relDir)
+		if err != nil {
+			return nil, err
+		}
+		if len(patterns) > 0 {
+			// Copy so sibling directories don't share appended rules
+			rules = append(append(IgnoreRules{}, rules...), patterns...)
+		}
+	}
+	return rules, nil
+}
+
+// Ignored reports whether the last rule matching the path excludes it
+func (ir IgnoreRules) Ignored(relPath string, isDir bool) bool {
+	for i := len(ir) - 1; i >= 0; i-- {
+		if ir[i].Match(relPath, isDir) {
+			return !ir[i].negate
+		}
+	}
+	return false
+}
+
+// readIgnoreFile parses one ignore file; a missing file has no patterns
+func readIgnoreFile(filePath, base string) ([]*Pattern, error) {
+	file, err := os.Open(filePath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error opening %s: %v", filePath, err)
+	}
+	defer file.Close()
+
+	patterns := []*Pattern{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		pattern, err := ParsePattern(line, base)
+		if err != nil {
+			return nil, fmt.Errorf("error in %s: %v", filePath, err)
+		}
+		patterns = append(patterns, pattern)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading %s: %v", filePath, err)
+	}
+	return patterns, nil
+}
+
+// MatchAny reports whether any of the patterns matches the path
+func MatchAny(patterns []*Pattern, relPath string, isDir bool) bool {
+	for _, pattern := range patterns {
+		if pattern.Match(relPath, isDir) {
+			return true
+		}
+	}
+	return false
+}
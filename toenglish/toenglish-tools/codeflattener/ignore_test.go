@@ -0,0 +1,141 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParsePattern(t *testing.T) {
+	tests := []struct {
+		glob  string
+		base  string
+		path  string
+		isDir bool
+		want  bool
+	}{
+		// Without a slash a pattern matches a name at any depth
+		{"*.log", "", "app.log", false, true},
+		{"*.log", "", "logs/app.log", false, true},
+		{"*.log", "", "app.log.txt", false, false},
+		{"vendor", "", "src/vendor", true, true},
+
+		// With a slash it is anchored to its base directory
+		{"/build", "", "build", true, true},
+		{"/build", "", "src/build", true, false},
+		{"docs/*.md", "", "docs/a.md", false, true},
+		{"docs/*.md", "", "docs/sub/a.md", false, false},
+		{"docs/*.md", "", "src/docs/a.md", false, false},
+		{"*.go", "pkg", "pkg/sub/a.go", false, true},
+		{"*.go", "pkg", "other/a.go", false, false},
+		{"/gen", "pkg", "pkg/gen", true, true},
+		{"/gen", "pkg", "gen", true, false},
+
+		// "**" matches any number of directories
+		{"**/testdata", "", "testdata", true, true},
+		{"**/testdata", "", "a/b/testdata", true, true},
+		{"docs/**", "", "docs/a/b.md", false, true},
+		{"docs/**", "", "docs", true, false},
+		{"a/**/z.txt", "", "a/z.txt", false, true},
+		{"a/**/z.txt", "", "a/b/c/z.txt", false, true},
+		{"src/**.go", "", "src/a/b.go", false, true},
+
+		// Wildcards and classes stay inside one path element
+		{"*.go", "", "a/b.go", false, true},
+		{"a*/b", "", "ax/y/b", false, false},
+		{"file?.txt", "", "file1.txt", false, true},
+		{"file?.txt", "", "file/.txt", false, false},
+		{"[abc].txt", "", "b.txt", false, true},
+		{"[!abc].txt", "", "b.txt", false, false},
+		{"[!abc].txt", "", "d.txt", false, true},
+		{`\#notes`, "", "#notes", false, true},
+		{`\!important`, "", "!important", false, true},
+		{"a+b.txt", "", "a+b.txt", false, true},
+		{"a+b.txt", "", "aab.txt", false, false},
+
+		// A trailing slash only matches directories
+		{"build/", "", "build", true, true},
+		{"build/", "", "build", false, false},
+		{"build/", "", "src/build", true, true},
+	}
+	for _, test := range tests {
+		pattern, err := ParsePattern(test.glob, test.base)
+		if err != nil {
+			t.Errorf("ParsePattern(%q): %v", test.glob, err)
+			continue
+		}
+		if got := pattern.Match(test.path, test.isDir); got != test.want {
+			t.Errorf("%q in %q matching %q (dir %v) = %v, want %v", test.glob, test.base, test.path, test.isDir, got, test.want)
+		}
+	}
+
+	for _, glob := range []string{"", "!", "/"} {
+		if _, err := ParsePattern(glob, ""); err == nil {
+			t.Errorf("ParsePattern(%q) should fail", glob)
+		}
+	}
+}
+
+func TestIgnoreRules(t *testing.T) {
+	root := t.TempDir()
+	ignoreFiles := map[string]string{
+		".gitignore":         "# build output\n*.log\nbuild/\n/secret.txt\n",
+		"app/.gitignore":     "!keep.log\ntmp\n",
+		"app/sub/.ignore":    "*.go\n!main.go\n",
+		"other/.gitignore":   "\n   \n",
+		"other/x/.gitignore": "keep.log\n",
+	}
+	for name, content := range ignoreFiles {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Load the rules on the way down, as the walk does
+	load := func(dirs ...string) IgnoreRules {
+		rules := IgnoreRules{}
+		for _, dir := range dirs {
+			var err error
+			if rules, err = rules.Load(filepath.Join(root, filepath.FromSlash(dir)), dir); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return rules
+	}
+	rootRules := load("")
+	appRules := load("", "app")
+	subRules := load("", "app", "app/sub")
+	otherRules := load("", "other", "other/x")
+
+	tests := []struct {
+		name  string
+		rules IgnoreRules
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{"root pattern", rootRules, "debug.log", false, true},
+		{"dir-only pattern", rootRules, "build", true, true},
+		{"dir-only pattern on a file", rootRules, "build", false, false},
+		{"anchored at root", rootRules, "secret.txt", false, true},
+		{"anchored elsewhere", appRules, "app/secret.txt", false, false},
+		{"parent pattern below", appRules, "app/debug.log", false, true},
+		{"re-included by a deeper file", appRules, "app/keep.log", false, false},
+		{"re-include only below its file", rootRules, "keep.log", false, true},
+		{"deeper pattern", appRules, "app/tmp", true, true},
+		{"deeper pattern outside its dir", otherRules, "other/tmp", true, false},
+		{".ignore files count too", subRules, "app/sub/util.go", false, true},
+		{"negation in .ignore", subRules, "app/sub/main.go", false, false},
+		{"sibling rules don't leak", otherRules, "other/x/keep.log", false, true},
+		{"blank lines", otherRules, "other/a.go", false, false},
+	}
+	for _, test := range tests {
+		if got := test.rules.Ignored(test.path, test.isDir); got != test.want {
+			t.Errorf("%s: Ignored(%q) = %v, want %v", test.name, test.path, got, test.want)
+		}
+	}
+}
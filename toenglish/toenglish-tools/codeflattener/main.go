@@ -1,103 +1,350 @@
 package main
 
 import (
+	"bufio"
+	"bytes"
+	"flag"
 	"fmt"
-	"io/ioutil"
+	"io"
 	"os"
-	"path/filepath"
+	"strconv"
 	"strings"
 )
 
-func main() {
-	// Change this to your root directory path
-	rootDir := "/Users/vishalvaibhav/Code/github-projects/kahoot-clone-nodejs/server"
-	// Change this to your desired output file path
-	outputFile := "combined_code.txt"
-
-	// Check if file exists and remove it
-	if _, err := os.Stat(outputFile); err == nil {
-		err = os.Remove(outputFile)
-		if err != nil {
-			fmt.Printf("Error removing existing file: %v\n", err)
-			return
+// stringList is a flag that can be repeated
+type stringList []string
+
+func (sl *stringList) String() string {
+	return strings.Join(*sl, ",")
+}
+
+func (sl *stringList) Set(value string) error {
+	*sl = append(*sl, value)
+	return nil
+}
+
+// byteSize is a flag holding a size such as 512KB or 2MB
+type byteSize int64
+
+func (bs *byteSize) String() string {
+	return strconv.FormatInt(int64(*bs), 10)
+}
+
+func (bs *byteSize) Set(value string) error {
+	size, err := parseSize(value)
+	if err != nil {
+		return err
+	}
+	*bs = byteSize(size)
+	return nil
+}
+
+// parseSize parses a byte count with an optional B, KB, MB or GB suffix
+func parseSize(value string) (int64, error) {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	multiplier := int64(1)
+	for _, unit := range []struct {
+		suffix string
+		size   int64
+	}{{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"B", 1}} {
+		if strings.HasSuffix(value, unit.suffix) {
+			multiplier = unit.size
+			value = strings.TrimSpace(strings.TrimSuffix(value, unit.suffix))
+			break
 		}
 	}
 
-	// Create/truncate output file
-	output, err := os.Create(outputFile)
-	if err != nil {
-		fmt.Printf("Error creating output file: %v\n", err)
-		return
+	size, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || size < 0 {
+		return 0, fmt.Errorf("invalid size '%s'", value)
 	}
-	defer output.Close()
+	return size * multiplier, nil
+}
 
-	// Walk through the directory
-	err = filepath.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
+// parsePatterns compiles the include or exclude globs given on the command line
+func parsePatterns(globs []string) ([]*Pattern, error) {
+	patterns := []*Pattern{}
+	for _, glob := range globs {
+		pattern, err := ParsePattern(glob, "")
 		if err != nil {
-			return err
+			return nil, err
 		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns, nil
+}
 
-		// Skip directories
-		if info.IsDir() {
-			return nil
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "unflatten" {
+		if err := runUnflattenCommand(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error unflattening: %v\n", err)
+			os.Exit(1)
 		}
+		return
+	}
 
-		// Skip hidden files and specific file types you want to exclude
-		if strings.HasPrefix(info.Name(), ".") || isExcludedFile(info.Name()) {
-			return nil
+	var roots, includes, excludes stringList
+	maxSize := byteSize(1 << 20)
+	var maxTotal byteSize
+
+	flag.Var(&roots, "root", "directory to flatten; repeat for several roots (default: the arguments, or .)")
+	outputFile := flag.String("o", "", "output file, or - for stdout (default combined_code with the format's extension)")
+	formatName := flag.String("format", FormatText, "output format: "+strings.Join(Formats(), ", "))
+	flag.Var(&includes, "include", "only flatten files matching this glob, e.g. '*.go' or 'src/**'; repeatable")
+	flag.Var(&excludes, "exclude", "skip files and directories matching this glob, e.g. 'vendor/' or '*_test.go'; repeatable")
+	flag.Var(&maxSize, "max-size", "skip files larger than this, e.g. 512KB or 2MB; 0 for no limit")
+	flag.Var(&maxTotal, "max-total", "stop adding files once the output would grow past this, e.g. 20MB; 0 for no limit")
+	followSymlinks := flag.Bool("follow-symlinks", false, "follow symbolic links to files and directories")
+	noIgnore := flag.Bool("no-ignore", false, "don't apply .gitignore and .ignore files")
+	secretsName := flag.String("secrets", string(SecretsRedact), "what to do with API keys, passwords and private keys found in files: redact, fail or off")
+	secretsAllowlist := flag.String("secrets-allowlist", "", "file of secrets to let through, one 'fingerprint:<hex>' or '<glob> [rule ...]' per line")
+	goSummary := flag.String("go-summary", "", "summarize .go files: strip (commented-out code), exported (declarations and signatures only), order (by package imports), comma-separated, or all")
+	chunkTokens := flag.Int("chunk-tokens", 0, "split the output into numbered chunks of at most this many estimated tokens, with a manifest; 0 writes one file")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: codeflattener [flags] [root ...]\n       codeflattener unflatten [flags] <combined file> ...\n\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	roots = append(roots, flag.Args()...)
+	if len(roots) == 0 {
+		roots = stringList{"."}
+	}
+
+	flattener := NewFlattener(roots)
+	flattener.MaxFileSize = int64(maxSize)
+	flattener.MaxTotalSize = int64(maxTotal)
+	flattener.FollowSymlinks = *followSymlinks
+	flattener.UseIgnoreFiles = !*noIgnore
+
+	format, err := ParseFormat(*formatName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+	if *outputFile == "" {
+		*outputFile = "combined_code" + FormatExtension(format)
+	}
+
+	if *goSummary != "" {
+		if flattener.GoSummary, err = ParseGoSummary(*goSummary); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(2)
 		}
+	}
 
-		// Read file contents
-		content, err := ioutil.ReadFile(path)
-		if err != nil {
-			return fmt.Errorf("error reading file %s: %v", path, err)
+	secretMode, err := ParseSecretMode(*secretsName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+	if secretMode != SecretsOff {
+		flattener.Secrets = NewSecretScanner()
+		if *secretsAllowlist != "" {
+			if err := flattener.Secrets.LoadAllowlist(*secretsAllowlist); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(2)
+			}
 		}
+	}
 
-		// Write file path as comment
-		relativePath, err := filepath.Rel(rootDir, path)
-		if err != nil {
-			return fmt.Errorf("error getting relative path for %s: %v", path, err)
+	if flattener.Include, err = parsePatterns(includes); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing -include: %v\n", err)
+		os.Exit(2)
+	}
+	if flattener.Exclude, err = parsePatterns(excludes); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing -exclude: %v\n", err)
+		os.Exit(2)
+	}
+
+	if *chunkTokens > 0 {
+		if *outputFile == "-" {
+			fmt.Fprintf(os.Stderr, "Error: -chunk-tokens needs an output file, not stdout\n")
+			os.Exit(2)
+		}
+		if err := runChunked(os.Stdout, flattener, *outputFile, format, *chunkTokens, secretMode); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
 		}
+		return
+	}
 
-		header := fmt.Sprintf("\n//%s\n\n", relativePath)
-		if _, err := output.WriteString(header); err != nil {
-			return fmt.Errorf("error writing header: %v", err)
+	// Messages go to stderr when the combined code goes to stdout
+	messages := os.Stdout
+	var output io.Writer
+	var pending *bytes.Buffer
+	if *outputFile == "-" {
+		messages = os.Stderr
+		output = os.Stdout
+		// Output can't be taken back from stdout, so in fail mode it waits for the secrets check
+		if secretMode == SecretsFail {
+			pending = &bytes.Buffer{}
+			output = pending
 		}
+	} else {
+		file, err := os.Create(*outputFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating output file: %v\n", err)
+			os.Exit(1)
+		}
+		defer file.Close()
+		output = file
+
+		// Don't flatten a previous run's output into this one
+		flattener.Skip(*outputFile)
+	}
+
+	buffered := bufio.NewWriter(output)
+	writer, err := NewWriter(format, buffered)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
 
-		// Write file contents
-		if _, err := output.Write(content); err != nil {
-			return fmt.Errorf("error writing content: %v", err)
+	count := 0
+	err = writer.Begin()
+	if err == nil {
+		err = flattener.Walk(func(file SourceFile) error {
+			count++
+			return writer.WriteFile(file)
+		})
+	}
+	if err == nil {
+		err = writer.End()
+	}
+	if err == nil {
+		err = buffered.Flush()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error walking directory: %v\n", err)
+		os.Exit(1)
+	}
+
+	printSkipReport(messages, flattener.Skipped)
+	printGoSummary(messages, flattener.GoSummary)
+	if err := checkSecrets(messages, flattener.Secrets, secretMode); err != nil {
+		// Secrets are redacted either way, but a failed run leaves no output behind
+		if *outputFile != "-" {
+			os.Remove(*outputFile)
+		}
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if pending != nil {
+		if _, err := pending.WriteTo(os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+			os.Exit(1)
 		}
+	}
+	fmt.Fprintf(messages, "Successfully combined %d code files (%s)!\n", count, formatSize(flattener.TotalSize))
+}
 
-		// Add a newline after each file
-		if _, err := output.WriteString("\n"); err != nil {
-			return fmt.Errorf("error writing newline: %v", err)
+// runChunked writes the files into token-budgeted chunks and a manifest, reporting to messages
+func runChunked(messages io.Writer, flattener *Flattener, outputFile, format string, budget int, secretMode SecretMode) error {
+	// Don't flatten a previous run's chunks into this one
+	for _, glob := range ChunkGlobs(outputFile) {
+		pattern, err := ParsePattern(glob, "")
+		if err != nil {
+			return err
 		}
+		flattener.Exclude = append(flattener.Exclude, pattern)
+	}
+	if err := RemoveStaleChunks(outputFile); err != nil {
+		return err
+	}
 
-		return nil
+	chunks, err := NewChunkWriter(outputFile, format, budget)
+	if err != nil {
+		return err
+	}
+	count := 0
+	err = flattener.Walk(func(file SourceFile) error {
+		count++
+		return chunks.Write(file)
 	})
+	if closeErr := chunks.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		RemoveStaleChunks(outputFile)
+		return fmt.Errorf("error walking directory: %v", err)
+	}
+
+	printSkipReport(messages, flattener.Skipped)
+	printGoSummary(messages, flattener.GoSummary)
+	if err := checkSecrets(messages, flattener.Secrets, secretMode); err != nil {
+		RemoveStaleChunks(outputFile)
+		return err
+	}
 
+	manifestPath, err := chunks.WriteManifest()
 	if err != nil {
-		fmt.Printf("Error walking directory: %v\n", err)
-		return
+		return err
 	}
 
-	fmt.Println("Successfully combined all code files!")
+	manifest := chunks.Manifest()
+	fmt.Fprintf(messages, "=== Chunk Summary ===\n")
+	for _, chunk := range manifest.Chunks {
+		fmt.Fprintf(messages, "%s: %d files, ~%d tokens\n", chunk.File, len(chunk.Files), chunk.Tokens)
+	}
+	fmt.Fprintf(messages, "\nSuccessfully combined %d code files into %d chunks of at most %d tokens (~%d in total), manifest in %s\n",
+		count, len(manifest.Chunks), budget, manifest.TotalTokens, manifestPath)
+	return nil
+}
+
+// checkSecrets prints the secret findings and fails in fail mode when there are any
+func checkSecrets(w io.Writer, scanner *SecretScanner, mode SecretMode) error {
+	if scanner == nil {
+		return nil
+	}
+	printSecretReport(w, scanner, mode)
+	if mode == SecretsFail && len(scanner.Findings) > 0 {
+		return fmt.Errorf("found %d secrets", len(scanner.Findings))
+	}
+	return nil
 }
 
-func isExcludedFile(filename string) bool {
-	// Add file extensions or patterns you want to exclude
-	excludedExtensions := []string{
-		".exe", ".dll", ".so", ".dylib",
-		".zip", ".tar", ".gz",
-		".jpg", ".png", ".gif",
-		".pdf", ".doc", ".docx",
+// printSkipReport lists the skipped files with their reason, then counts them by reason
+func printSkipReport(w io.Writer, skipped []SkippedFile) {
+	if len(skipped) == 0 {
+		return
 	}
 
-	for _, ext := range excludedExtensions {
-		if strings.HasSuffix(filename, ext) {
-			return true
+	fmt.Fprintf(w, "\n=== Skipped Files ===\n")
+	counts := map[SkipReason]int{}
+	reasons := []SkipReason{}
+	for _, file := range skipped {
+		if counts[file.Reason] == 0 {
+			reasons = append(reasons, file.Reason)
+		}
+		counts[file.Reason]++
+
+		reason := string(file.Reason)
+		if file.Detail != "" {
+			reason += ": " + file.Detail
+		}
+		if file.Size > 0 {
+			reason += ", " + formatSize(file.Size)
 		}
+		fmt.Fprintf(w, "  %s (%s)\n", file.Path, reason)
+	}
+
+	fmt.Fprintf(w, "\n")
+	for _, reason := range reasons {
+		fmt.Fprintf(w, "%-22s %d\n", string(reason)+":", counts[reason])
+	}
+	fmt.Fprintf(w, "\n")
+}
+
+// formatSize prints a byte count with a binary unit
+func formatSize(size int64) string {
+	switch {
+	case size >= 1<<30:
+		return fmt.Sprintf("%.1f GB", float64(size)/(1<<30))
+	case size >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1<<20))
+	case size >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(size)/(1<<10))
 	}
-	return false
+	return fmt.Sprintf("%d B", size)
 }
@@ -0,0 +1,277 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ParsedFile is a file read back from a combined file
+type ParsedFile struct {
+	Path    string
+	Content []byte
+
+	// SHA256 is the hash recorded at flatten time, for the formats that store one
+	SHA256 string
+}
+
+// partSuffix marks one part of a file split across chunks
+var partSuffix = regexp.MustCompile(` \(part (\d+) of (\d+)\)$`)
+
+// textHeader matches the "//path" header line the text writer puts before every file
+var textHeader = regexp.MustCompile(`\n//(.+)\n\n`)
+
+// noFinalNewline follows the Markdown heading of a file that doesn't end in a newline,
+// since the code block adds one
+const noFinalNewline = " (no newline at end of file)"
+
+// DetectFormat guesses the format of a combined file from its extension, then its contents
+func DetectFormat(name string, data []byte) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json":
+		return FormatJSON
+	case ".xml":
+		return FormatXML
+	case ".md", ".markdown":
+		return FormatMarkdown
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("[")):
+		return FormatJSON
+	case bytes.HasPrefix(trimmed, []byte("<?xml")), bytes.HasPrefix(trimmed, []byte("<files")):
+		return FormatXML
+	case bytes.HasPrefix(trimmed, []byte("## ")):
+		return FormatMarkdown
+	}
+	return FormatText
+}
+
+// ParseCombined reads the files of a combined file in the given format
+func ParseCombined(data []byte, format string) ([]ParsedFile, error) {
+	switch format {
+	case FormatJSON:
+		return parseJSON(data)
+	case FormatXML:
+		return parseXML(data)
+	case FormatMarkdown:
+		return parseMarkdown(data)
+	case FormatText:
+		return parseText(data), nil
+	}
+	return nil, fmt.Errorf("unknown format '%s'", format)
+}
+
+// parseJSON reads an array of FileRecord objects
+func parseJSON(data []byte) ([]ParsedFile, error) {
+	var records []FileRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("error parsing JSON: %v", err)
+	}
+
+	files := make([]ParsedFile, len(records))
+	for i, record := range records {
+		files[i] = ParsedFile{Path: record.Path, Content: []byte(record.Content), SHA256: record.SHA256}
+	}
+	return files, nil
+}
+
+// parseXML reads a <files> document
+func parseXML(data []byte) ([]ParsedFile, error) {
+	var document struct {
+		Files []struct {
+			Path     string `xml:"path,attr"`
+			SHA256   string `xml:"sha256,attr"`
+			Encoding string `xml:"encoding,attr"`
+			Content  string `xml:",chardata"`
+		} `xml:"file"`
+	}
+	if err := xml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("error parsing XML: %v", err)
+	}
+
+	files := make([]ParsedFile, len(document.Files))
+	for i, file := range document.Files {
+		content := []byte(file.Content)
+		switch file.Encoding {
+		case "":
+		case "base64":
+			decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(file.Content))
+			if err != nil {
+				return nil, fmt.Errorf("error decoding %s: %v", file.Path, err)
+			}
+			content = decoded
+		default:
+			return nil, fmt.Errorf("unknown encoding %q of %s", file.Encoding, file.Path)
+		}
+		files[i] = ParsedFile{Path: file.Path, Content: content, SHA256: file.SHA256}
+	}
+	return files, nil
+}
+
+// parseMarkdown reads "## path" headings each followed by a fenced code block.
+// Text outside the blocks, such as notes added by a reviewer, is ignored.
+func parseMarkdown(data []byte) ([]ParsedFile, error) {
+	lines := strings.SplitAfter(string(data), "\n")
+	files := []ParsedFile{}
+
+	for i := 0; i < len(lines); i++ {
+		heading := strings.TrimRight(lines[i], "\r\n")
+		if !strings.HasPrefix(heading, "## ") {
+			continue
+		}
+		filePath := strings.TrimSpace(strings.TrimPrefix(heading, "## "))
+		trimNewline := strings.HasSuffix(filePath, noFinalNewline)
+		filePath = strings.TrimSuffix(filePath, noFinalNewline)
+
+		// The fence opens on the next non-blank line
+		j := i + 1
+		for j < len(lines) && strings.TrimSpace(lines[j]) == "" {
+			j++
+		}
+		if j >= len(lines) {
+			break
+		}
+		opening := strings.TrimRight(lines[j], "\r\n")
+		fence := opening[:longestRunPrefix(opening, '`')]
+		if len(fence) < 3 {
+			continue
+		}
+
+		var content strings.Builder
+		closed := false
+		for j++; j < len(lines); j++ {
+			if strings.TrimRight(lines[j], "\r\n") == fence {
+				closed = true
+				break
+			}
+			content.WriteString(lines[j])
+		}
+		if !closed {
+			return nil, fmt.Errorf("code block of %s is not closed", filePath)
+		}
+
+		text := content.String()
+		if trimNewline {
+			text = strings.TrimSuffix(text, "\n")
+		}
+		files = append(files, ParsedFile{Path: filePath, Content: []byte(text)})
+		i = j
+	}
+	return files, nil
+}
+
+// longestRunPrefix returns the number of leading c bytes in text
+func longestRunPrefix(text string, c byte) int {
+	n := 0
+	for n < len(text) && text[n] == c {
+		n++
+	}
+	return n
+}
+
+// parseText reads "//path" headers, each followed by the file and a newline.
+// A header must look like a path, so a "//comment" line between blank lines
+// in the code is not taken for one. The writer puts "./" before the paths that
+// wouldn't, which is dropped again here.
+func parseText(data []byte) []ParsedFile {
+	text := string(data)
+	matches := textHeader.FindAllStringSubmatchIndex(text, -1)
+
+	headers := [][]int{}
+	for _, match := range matches {
+		if looksLikePath(partSuffix.ReplaceAllString(text[match[2]:match[3]], "")) {
+			headers = append(headers, match)
+		}
+	}
+
+	files := []ParsedFile{}
+	for i, header := range headers {
+		end := len(text)
+		if i+1 < len(headers) {
+			end = headers[i+1][0]
+		}
+		content := strings.TrimSuffix(text[header[1]:end], "\n")
+		files = append(files, ParsedFile{Path: strings.TrimPrefix(text[header[2]:header[3]], "./"), Content: []byte(content)})
+	}
+	return files
+}
+
+// looksLikePath reports whether a header names a file: a relative path with an
+// extension or a directory, or a well-known file name such as Makefile
+func looksLikePath(name string) bool {
+	if name == "" || strings.HasPrefix(name, "/") || strings.Contains(name, "://") {
+		return false
+	}
+	base := path.Base(name)
+	if _, ok := languageNames[base]; ok {
+		return true
+	}
+	return strings.Contains(name, "/") || (strings.Contains(base, ".") && !strings.HasSuffix(base, "."))
+}
+
+// JoinParts merges the parts of files split across chunks, keeping the order of first appearance
+func JoinParts(files []ParsedFile) ([]ParsedFile, error) {
+	type pieces struct {
+		parts map[int][]byte
+		total int
+	}
+
+	joined := []ParsedFile{}
+	split := map[string]*pieces{}
+	order := map[string]int{}
+	for _, file := range files {
+		match := partSuffix.FindStringSubmatch(file.Path)
+		if match == nil {
+			joined = append(joined, file)
+			continue
+		}
+
+		filePath := partSuffix.ReplaceAllString(file.Path, "")
+		part, _ := strconv.Atoi(match[1])
+		total, _ := strconv.Atoi(match[2])
+		if split[filePath] == nil {
+			split[filePath] = &pieces{parts: map[int][]byte{}, total: total}
+			order[filePath] = len(joined)
+			joined = append(joined, ParsedFile{Path: filePath})
+		}
+		split[filePath].parts[part] = file.Content
+	}
+
+	paths := make([]string, 0, len(split))
+	for filePath := range split {
+		paths = append(paths, filePath)
+	}
+	sort.Strings(paths)
+	for _, filePath := range paths {
+		pieces := split[filePath]
+		var content []byte
+		for part := 1; part <= pieces.total; part++ {
+			piece, ok := pieces.parts[part]
+			if !ok {
+				return nil, fmt.Errorf("part %d of %d of %s is missing", part, pieces.total, filePath)
+			}
+			content = append(content, piece...)
+		}
+		joined[order[filePath]].Content = content
+	}
+	return joined, nil
+}
+
+// safeRelPath checks that a parsed path stays inside the target directory
+func safeRelPath(filePath string) (string, error) {
+	cleaned := path.Clean(filePath)
+	if path.IsAbs(cleaned) || cleaned == ".." || strings.HasPrefix(cleaned, "../") || cleaned == "." {
+		return "", fmt.Errorf("unsafe path '%s'", filePath)
+	}
+	return cleaned, nil
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// roundTripFiles are awkward files every format must give back unchanged
+var roundTripFiles = []SourceFile{
+	{Path: "main.go", Content: []byte("package main\n\nfunc main() {}\n")},
+	{Path: "dir/no newline.txt", Content: []byte("last line")},
+	{Path: "empty.txt", Content: []byte{}},
+	{Path: "NOTES", Content: []byte("a top-level file without an extension\n")},
+	{Path: "docs/readme.md", Content: []byte("```go\nfenced\n```\n\n## not a heading\n")},
+	{Path: "web/page.xml", Content: []byte("<![CDATA[ nested ]]> & <tag/>\n")},
+	{Path: "windows.bat", Content: []byte("echo one\r\necho two\r\n")},
+	{Path: "log.txt", Content: []byte("\x1b[31mred\x1b[0m\n")},
+	{Path: "comment.go", Content: []byte("x := 1\n\n//not/a/header\n\ny := 2\n")},
+	{Path: "unicode.txt", Content: []byte("héllo, wörld ✓\n")},
+}
+
+func TestParseRoundTrip(t *testing.T) {
+	for _, format := range Formats() {
+		t.Run(format, func(t *testing.T) {
+			var combined bytes.Buffer
+			writer, err := NewWriter(format, &combined)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := writer.Begin(); err != nil {
+				t.Fatal(err)
+			}
+			for _, file := range roundTripFiles {
+				// The text format can't tell this file's "//" line from a header
+				if format == FormatText && file.Path == "comment.go" {
+					continue
+				}
+				if err := writer.WriteFile(file); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := writer.End(); err != nil {
+				t.Fatal(err)
+			}
+
+			if detected := DetectFormat("combined", combined.Bytes()); detected != format {
+				t.Errorf("DetectFormat = %s, want %s", detected, format)
+			}
+			parsed, err := ParseCombined(combined.Bytes(), format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := map[string]string{}
+			for _, file := range parsed {
+				got[file.Path] = string(file.Content)
+				if file.SHA256 != "" && file.SHA256 != fileSHA256(file.Content) {
+					t.Errorf("%s: SHA-256 doesn't match the parsed content", file.Path)
+				}
+			}
+			for _, file := range roundTripFiles {
+				if format == FormatText && file.Path == "comment.go" {
+					continue
+				}
+				content, ok := got[file.Path]
+				if !ok {
+					t.Errorf("%s is missing, parsed %d files", file.Path, len(parsed))
+					continue
+				}
+				if content != string(file.Content) {
+					t.Errorf("%s = %q, want %q", file.Path, content, file.Content)
+				}
+			}
+		})
+	}
+}
+
+func TestParseRoundTripParts(t *testing.T) {
+	content := strings.Repeat("a line of code\n", 40) + "no newline"
+	for _, format := range Formats() {
+		t.Run(format, func(t *testing.T) {
+			parts := splitByTokens([]byte(content), 50)
+			var combined bytes.Buffer
+			writer, _ := NewWriter(format, &combined)
+			writer.Begin()
+			for i, part := range parts {
+				if err := writer.WriteFile(filePart(SourceFile{Path: "big.go"}, part, i, len(parts))); err != nil {
+					t.Fatal(err)
+				}
+			}
+			writer.End()
+
+			parsed, err := ParseCombined(combined.Bytes(), format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			joined, err := JoinParts(parsed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(joined) != 1 || joined[0].Path != "big.go" || string(joined[0].Content) != content {
+				t.Errorf("joined %d parts into %+v", len(parts), joined)
+			}
+		})
+	}
+}
+
+func TestHeaderPathRejected(t *testing.T) {
+	tests := []struct {
+		format string
+		path   string
+	}{
+		{FormatText, "two\nlines.txt"},
+		{FormatText, " leading.txt"},
+		{FormatMarkdown, "carriage\rreturn.md"},
+	}
+	for _, test := range tests {
+		writer, _ := NewWriter(test.format, &bytes.Buffer{})
+		if err := writer.WriteFile(SourceFile{Path: test.path, Content: []byte("x\n")}); err == nil {
+			t.Errorf("%s writer accepted the path %q", test.format, test.path)
+		}
+	}
+}
+
+func TestLooksLikePath(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"main.go", true},
+		{"dir/file", true},
+		{"with space/file name.txt", true},
+		{"Makefile", true},
+		{"NOTES", false},
+		{"ends with a dot.", false},
+		{"/etc/passwd", false},
+		{"https://example.com/x.go", false},
+		{"", false},
+	}
+	for _, test := range tests {
+		if got := looksLikePath(test.name); got != test.want {
+			t.Errorf("looksLikePath(%q) = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestJoinPartsMissing(t *testing.T) {
+	files := []ParsedFile{
+		{Path: "a.go (part 1 of 3)", Content: []byte("one")},
+		{Path: "a.go (part 3 of 3)", Content: []byte("three")},
+	}
+	if _, err := JoinParts(files); err == nil {
+		t.Error("JoinParts accepted a file with a missing part")
+	}
+}
+
+func TestSafeRelPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+		ok   bool
+	}{
+		{"a/b.go", "a/b.go", true},
+		{"a/../b.go", "b.go", true},
+		{"../escape.go", "", false},
+		{"/abs.go", "", false},
+		{".", "", false},
+	}
+	for _, test := range tests {
+		got, err := safeRelPath(test.path)
+		if (err == nil) != test.ok || got != test.want {
+			t.Errorf("safeRelPath(%q) = %q, %v; want %q, ok %v", test.path, got, err, test.want, test.ok)
+		}
+	}
+}
@@ -0,0 +1,402 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// SecretMode is what the flattener does with the secrets it finds
+type SecretMode string
+
+// Secret modes
+const (
+	SecretsRedact SecretMode = "redact"
+	SecretsFail   SecretMode = "fail"
+	SecretsOff    SecretMode = "off"
+)
+
+// ParseSecretMode checks a -secrets value
+func ParseSecretMode(name string) (SecretMode, error) {
+	switch mode := SecretMode(strings.ToLower(strings.TrimSpace(name))); mode {
+	case SecretsRedact, SecretsFail, SecretsOff:
+		return mode, nil
+	}
+	return "", fmt.Errorf("unknown secrets mode '%s', want redact, fail or off", name)
+}
+
+// SecretRule finds one kind of secret
+type SecretRule struct {
+	Name        string
+	Description string
+	Regex       *regexp.Regexp
+
+	// Group is the submatch holding the secret itself; 0 is the whole match
+	Group int
+
+	// Check filters the matched values, such as placeholders; nil accepts every match
+	Check func(value string) bool
+}
+
+// secretQuote matches the quotes around a string literal
+const secretQuote = "[\"'`]"
+
+// DefaultSecretRules are the rules of a new scanner. When matches overlap, the earlier rule wins.
+var DefaultSecretRules = []SecretRule{
+	{
+		Name:        "private-key",
+		Description: "PEM private key block",
+		Regex:       regexp.MustCompile(`-----BEGIN[A-Z0-9 ]*PRIVATE KEY-----\s*([\s\S]*?)\s*-----END[A-Z0-9 ]*PRIVATE KEY-----`),
+		Group:       1,
+	},
+	{
+		Name:        "aws-access-key",
+		Description: "AWS access key ID",
+		Regex:       regexp.MustCompile(`\b((?:AKIA|ASIA|ABIA|ACCA)[0-9A-Z]{16})\b`),
+		Group:       1,
+	},
+	{
+		Name:        "aws-secret-key",
+		Description: "AWS secret access key",
+		Regex:       regexp.MustCompile(`(?i)aws_?secret_?(?:access_?)?key\w*["']?\s*(?::=|=|:)\s*["']?([A-Za-z0-9/+=]{40})\b`),
+		Group:       1,
+	},
+	{
+		Name:        "github-token",
+		Description: "GitHub token",
+		Regex:       regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{36,255}|github_pat_[A-Za-z0-9_]{22,255})\b`),
+		Group:       1,
+	},
+	{
+		Name:        "google-api-key",
+		Description: "Google API key, such as a Gemini key",
+		Regex:       regexp.MustCompile(`\b(AIza[0-9A-Za-z_\-]{35})`),
+		Group:       1,
+	},
+	{
+		Name:        "url-key",
+		Description: "API key or token in a URL query",
+		Regex:       regexp.MustCompile(`[?&](?:key|api_?key|apikey|access_token|token)=([A-Za-z0-9_\-.~%]{16,})`),
+		Group:       1,
+		Check:       notPlaceholder,
+	},
+	{
+		Name:        "dsn-password",
+		Description: "password in a connection URL",
+		Regex:       regexp.MustCompile(`\b[a-zA-Z][a-zA-Z0-9+.\-]*://[^\s:/@"'` + "`" + `]+:([^\s@/"'` + "`" + `]+)@`),
+		Group:       1,
+		Check:       notPlaceholder,
+	},
+	{
+		Name:        "dsn-password",
+		Description: "password in a key=value connection string",
+		Regex:       regexp.MustCompile(`(?i)\b(?:password|pwd)=([^\s"'` + "`" + `&;]+)`),
+		Group:       1,
+		Check:       notPlaceholder,
+	},
+	{
+		Name:        "password-assignment",
+		Description: "string literal assigned to a password, secret or key",
+		Regex: regexp.MustCompile(`(?i)\b\w*(?:password|passwd|secret|api_?key|access_?key|auth_?token|private_?key)\w*["']?\s*(?::=|=|:)\s*` +
+			secretQuote + `([^"'` + "`" + `\s]{6,})` + secretQuote),
+		Group: 1,
+		Check: notPlaceholder,
+	},
+	{
+		Name:        "high-entropy",
+		Description: "random-looking string literal",
+		Regex:       regexp.MustCompile(secretQuote + `([A-Za-z0-9+/=_\-]{20,})` + secretQuote),
+		Group:       1,
+		Check:       isHighEntropy,
+	},
+}
+
+// placeholderWords are values that stand in for a secret rather than being one
+var placeholderWords = []string{"password", "changeme", "example", "secret", "your", "dummy", "redact", "placeholder"}
+
+// notPlaceholder rejects format verbs, template variables and obvious stand-ins
+func notPlaceholder(value string) bool {
+	if strings.ContainsAny(value, "%<>{}$") {
+		return false
+	}
+	if strings.Trim(value, "*xX.") == "" {
+		return false
+	}
+	lower := strings.ToLower(value)
+	for _, word := range placeholderWords {
+		if strings.Contains(lower, word) {
+			return false
+		}
+	}
+	return true
+}
+
+// minHexEntropy and minBase64Entropy are the Shannon entropies in bits per character
+// above which a string literal looks random. Hex strings need 32 characters.
+const (
+	minHexEntropy    = 3.0
+	minBase64Entropy = 3.8
+)
+
+// isHighEntropy reports whether a token looks like a random key: a long hex string,
+// or a mix of upper case, lower case and digits with high entropy
+func isHighEntropy(value string) bool {
+	var hasUpper, hasLower, hasDigit, isHex = false, false, false, true
+	for _, c := range value {
+		switch {
+		case c >= 'A' && c <= 'Z':
+			hasUpper = true
+		case c >= 'a' && c <= 'z':
+			hasLower = true
+		case c >= '0' && c <= '9':
+			hasDigit = true
+		}
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			isHex = false
+		}
+	}
+
+	entropy := shannonEntropy(value)
+	if isHex {
+		return len(value) >= 32 && hasDigit && entropy >= minHexEntropy
+	}
+	return hasUpper && hasLower && hasDigit && entropy >= minBase64Entropy
+}
+
+// shannonEntropy returns the entropy of the characters of text in bits per character
+func shannonEntropy(text string) float64 {
+	counts := map[rune]int{}
+	total := 0
+	for _, c := range text {
+		counts[c]++
+		total++
+	}
+
+	entropy := 0.0
+	for _, count := range counts {
+		p := float64(count) / float64(total)
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// SecretFinding is a secret found in a file. It never holds the secret itself.
+type SecretFinding struct {
+	Path string
+	Line int
+	Rule string
+
+	// Fingerprint identifies the secret in allowlists without revealing it
+	Fingerprint string
+
+	// Preview shows the first characters of the secret
+	Preview string
+}
+
+// allowEntry lets findings through by path and rule, or by fingerprint
+type allowEntry struct {
+	pattern     *Pattern
+	rules       map[string]bool
+	fingerprint string
+}
+
+// SecretScanner finds secrets in file contents and redacts them
+type SecretScanner struct {
+	Rules []SecretRule
+
+	// Findings lists the secrets found by Scan, in scan order
+	Findings []SecretFinding
+
+	// Allowed counts the matches let through by the allowlist
+	Allowed int
+
+	allowlist []allowEntry
+}
+
+// NewSecretScanner creates a scanner with the default rules and an empty allowlist
+func NewSecretScanner() *SecretScanner {
+	return &SecretScanner{Rules: DefaultSecretRules}
+}
+
+// LoadAllowlist reads an allowlist file. Every line is either "fingerprint:<hex>",
+// as printed in the findings report, or a path glob optionally followed by the
+// rule names it allows; a glob alone allows every rule. # starts a comment.
+func (ss *SecretScanner) LoadAllowlist(filePath string) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("error opening allowlist: %v", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if fingerprint, ok := strings.CutPrefix(line, "fingerprint:"); ok {
+			ss.allowlist = append(ss.allowlist, allowEntry{fingerprint: strings.ToLower(strings.TrimSpace(fingerprint))})
+			continue
+		}
+
+		fields := strings.Fields(line)
+		pattern, err := ParsePattern(fields[0], "")
+		if err != nil {
+			return fmt.Errorf("error in %s line %d: %v", filePath, lineNumber, err)
+		}
+		entry := allowEntry{pattern: pattern}
+		if len(fields) > 1 {
+			entry.rules = map[string]bool{}
+			for _, rule := range fields[1:] {
+				entry.rules[rule] = true
+			}
+		}
+		ss.allowlist = append(ss.allowlist, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("error reading allowlist: %v", err)
+	}
+	return nil
+}
+
+// allowed reports whether the allowlist lets a finding through
+func (ss *SecretScanner) allowed(finding SecretFinding) bool {
+	for _, entry := range ss.allowlist {
+		if entry.fingerprint != "" {
+			if entry.fingerprint == finding.Fingerprint {
+				return true
+			}
+			continue
+		}
+		if entry.pattern.Match(finding.Path, false) && (entry.rules == nil || entry.rules[finding.Rule]) {
+			return true
+		}
+	}
+	return false
+}
+
+// secretMatch is the byte range of one secret in a file
+type secretMatch struct {
+	start, end int
+	rule       string
+}
+
+// Scan records the secrets in a file and returns its contents with every one
+// not allowlisted replaced by [REDACTED:<rule>]
+func (ss *SecretScanner) Scan(filePath string, content []byte) []byte {
+	matches := []secretMatch{}
+	overlaps := func(start, end int) bool {
+		for _, match := range matches {
+			if start < match.end && match.start < end {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, rule := range ss.Rules {
+		for _, loc := range rule.Regex.FindAllSubmatchIndex(content, -1) {
+			start, end := loc[2*rule.Group], loc[2*rule.Group+1]
+			if start < 0 || start == end || overlaps(start, end) {
+				continue
+			}
+			if rule.Check != nil && !rule.Check(string(content[start:end])) {
+				continue
+			}
+			matches = append(matches, secretMatch{start: start, end: end, rule: rule.Name})
+		}
+	}
+	if len(matches) == 0 {
+		return content
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].start < matches[j].start
+	})
+
+	var redacted bytes.Buffer
+	last, line := 0, 1
+	for _, match := range matches {
+		line += bytes.Count(content[last:match.start], []byte("\n"))
+		redacted.Write(content[last:match.start])
+		last = match.start
+
+		secret := content[match.start:match.end]
+		finding := SecretFinding{
+			Path:        filePath,
+			Line:        line,
+			Rule:        match.rule,
+			Fingerprint: secretFingerprint(secret),
+			Preview:     secretPreview(string(secret)),
+		}
+		if ss.allowed(finding) {
+			ss.Allowed++
+			continue
+		}
+		ss.Findings = append(ss.Findings, finding)
+
+		line += bytes.Count(secret, []byte("\n"))
+		redacted.WriteString("[REDACTED:" + match.rule + "]")
+		last = match.end
+	}
+	redacted.Write(content[last:])
+	return redacted.Bytes()
+}
+
+// secretFingerprint returns the first 16 hex digits of the secret's SHA-256
+func secretFingerprint(secret []byte) string {
+	sum := sha256.Sum256(secret)
+	return hex.EncodeToString(sum[:8])
+}
+
+// secretPreview keeps the first two characters of a secret and masks the rest
+func secretPreview(secret string) string {
+	runes := []rune(strings.Split(secret, "\n")[0])
+	if len(runes) <= 8 {
+		return strings.Repeat("*", len(runes))
+	}
+	return string(runes[:2]) + strings.Repeat("*", min(len(runes)-2, 12))
+}
+
+// printSecretReport lists the findings with their rule and fingerprint, then counts them by rule
+func printSecretReport(w io.Writer, scanner *SecretScanner, mode SecretMode) {
+	if len(scanner.Findings) == 0 && scanner.Allowed == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "=== Secret Findings ===\n")
+	counts := map[string]int{}
+	rules := []string{}
+	for _, finding := range scanner.Findings {
+		if counts[finding.Rule] == 0 {
+			rules = append(rules, finding.Rule)
+		}
+		counts[finding.Rule]++
+		fmt.Fprintf(w, "  %s:%d %s %s (fingerprint:%s)\n", finding.Path, finding.Line, finding.Rule, finding.Preview, finding.Fingerprint)
+	}
+
+	fmt.Fprintf(w, "\n")
+	for _, rule := range rules {
+		fmt.Fprintf(w, "%-22s %d\n", rule+":", counts[rule])
+	}
+	if scanner.Allowed > 0 {
+		fmt.Fprintf(w, "%-22s %d\n", "allowlisted:", scanner.Allowed)
+	}
+
+	switch {
+	case len(scanner.Findings) == 0:
+	case mode == SecretsFail:
+		fmt.Fprintf(w, "\nFound %d secrets, failing. Remove them or allowlist them with -secrets-allowlist.\n", len(scanner.Findings))
+	default:
+		fmt.Fprintf(w, "\nRedacted %d secrets in the output.\n", len(scanner.Findings))
+	}
+	fmt.Fprintf(w, "\n")
+}
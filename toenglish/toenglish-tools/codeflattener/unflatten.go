@@ -0,0 +1,277 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ChangeStatus compares a file of the combined file with the original tree
+type ChangeStatus string
+
+// Change statuses
+const (
+	StatusUnchanged ChangeStatus = "unchanged"
+	StatusChanged   ChangeStatus = "changed"
+	StatusAdded     ChangeStatus = "added"
+	StatusDeleted   ChangeStatus = "deleted"
+)
+
+// FileChange is the status of one file
+type FileChange struct {
+	Path   string
+	Status ChangeStatus
+
+	// EditedAfterFlatten is set when the content no longer matches the hash recorded at flatten time
+	EditedAfterFlatten bool
+
+	// OriginalMoved is set when the original file no longer matches the recorded hash,
+	// i.e. it changed in the tree since the flatten
+	OriginalMoved bool
+}
+
+// ReadCombined parses combined files, chunks or a chunk manifest, and joins split files.
+// format is "auto" to detect the format of every file.
+func ReadCombined(inputs []string, format string) ([]ParsedFile, error) {
+	files := []ParsedFile{}
+	for _, input := range expandManifests(inputs) {
+		data, err := os.ReadFile(input)
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s: %v", input, err)
+		}
+
+		inputFormat := format
+		if inputFormat == "auto" {
+			inputFormat = DetectFormat(input, data)
+		}
+		parsed, err := ParseCombined(data, inputFormat)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s: %v", input, err)
+		}
+		files = append(files, parsed...)
+	}
+	return JoinParts(files)
+}
+
+// expandManifests replaces every chunk manifest with the chunks it lists
+func expandManifests(inputs []string) []string {
+	expanded := []string{}
+	for _, input := range inputs {
+		data, err := os.ReadFile(input)
+		var manifest Manifest
+		if err != nil || filepath.Ext(input) != ".json" || json.Unmarshal(data, &manifest) != nil || len(manifest.Chunks) == 0 {
+			expanded = append(expanded, input)
+			continue
+		}
+		for _, chunk := range manifest.Chunks {
+			expanded = append(expanded, filepath.Join(filepath.Dir(input), chunk.File))
+		}
+	}
+	return expanded
+}
+
+// CompareTree compares parsed files with the files the flattener selects in the
+// original tree. Files selected there but missing from the combined file are deleted.
+func CompareTree(files []ParsedFile, original *Flattener) ([]FileChange, map[string][]byte, error) {
+	originals := map[string][]byte{}
+	if original != nil {
+		err := original.Walk(func(file SourceFile) error {
+			originals[file.Path] = file.Content
+			return nil
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	changes := []FileChange{}
+	seen := map[string]bool{}
+	for _, file := range files {
+		seen[file.Path] = true
+		change := FileChange{Path: file.Path, Status: StatusAdded}
+		if file.SHA256 != "" {
+			change.EditedAfterFlatten = fileSHA256(file.Content) != file.SHA256
+		}
+
+		if content, ok := originals[file.Path]; ok {
+			change.Status = StatusUnchanged
+			if !bytes.Equal(content, file.Content) {
+				change.Status = StatusChanged
+			}
+			if file.SHA256 != "" {
+				change.OriginalMoved = fileSHA256(content) != file.SHA256
+			}
+		}
+		changes = append(changes, change)
+	}
+
+	for filePath := range originals {
+		if !seen[filePath] {
+			changes = append(changes, FileChange{Path: filePath, Status: StatusDeleted})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes, originals, nil
+}
+
+// WriteTree writes the parsed files under dir, refusing paths that leave it
+func WriteTree(dir string, files []ParsedFile) error {
+	for _, file := range files {
+		relPath, err := safeRelPath(file.Path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dir, filepath.FromSlash(relPath))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return fmt.Errorf("error creating directory for %s: %v", file.Path, err)
+		}
+		if err := os.WriteFile(target, file.Content, 0644); err != nil {
+			return fmt.Errorf("error writing %s: %v", file.Path, err)
+		}
+	}
+	return nil
+}
+
+// runUnflattenCommand rebuilds a directory tree from combined files and compares it with the original
+func runUnflattenCommand(args []string) error {
+	fs := flag.NewFlagSet("unflatten", flag.ExitOnError)
+	outDir := fs.String("out", "", "directory to recreate the files in; empty only reports the changes")
+	originalDir := fs.String("original", "", "the flattened root, to compare against")
+	formatName := fs.String("format", "auto", "format of the combined files: auto, "+strings.Join(Formats(), ", "))
+	showDiff := fs.Bool("diff", true, "print a unified diff of every changed file")
+	var includes, excludes stringList
+	fs.Var(&includes, "include", "the -include globs used when flattening, to find deleted files; repeatable")
+	fs.Var(&excludes, "exclude", "the -exclude globs used when flattening, to find deleted files; repeatable")
+	maxSize := byteSize(1 << 20)
+	fs.Var(&maxSize, "max-size", "the -max-size used when flattening, to find deleted files")
+	noIgnore := fs.Bool("no-ignore", false, "the original was flattened with -no-ignore")
+	secretsName := fs.String("secrets", string(SecretsRedact), "the -secrets used when flattening; the original is redacted the same way unless it was off")
+	secretsAllowlist := fs.String("secrets-allowlist", "", "the -secrets-allowlist used when flattening")
+	goSummary := fs.String("go-summary", "", "the -go-summary used when flattening; the original is summarized the same way")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: codeflattener unflatten [flags] <combined file or manifest> ...\n\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return fmt.Errorf("no combined file given")
+	}
+
+	format := *formatName
+	if format != "auto" {
+		var err error
+		if format, err = ParseFormat(format); err != nil {
+			return err
+		}
+	}
+
+	files, err := ReadCombined(fs.Args(), format)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if _, err := safeRelPath(file.Path); err != nil {
+			return err
+		}
+	}
+
+	if *outDir != "" {
+		if err := WriteTree(*outDir, files); err != nil {
+			return err
+		}
+		fmt.Printf("Recreated %d files under %s\n", len(files), *outDir)
+	}
+
+	if *originalDir == "" {
+		return nil
+	}
+
+	original := NewFlattener([]string{*originalDir})
+	original.UseIgnoreFiles = !*noIgnore
+	original.MaxFileSize = int64(maxSize)
+	if original.Include, err = parsePatterns(includes); err != nil {
+		return err
+	}
+	if original.Exclude, err = parsePatterns(excludes); err != nil {
+		return err
+	}
+
+	// A summarized combined file only matches a summarized original. The order
+	// doesn't matter here, since the files are compared by path.
+	if *goSummary != "" {
+		if original.GoSummary, err = ParseGoSummary(*goSummary); err != nil {
+			return err
+		}
+	}
+
+	// The combined file holds redacted secrets, so the original is redacted
+	// before comparing; its diff lines then never show a secret either
+	secretMode, err := ParseSecretMode(*secretsName)
+	if err != nil {
+		return err
+	}
+	if secretMode != SecretsOff {
+		original.Secrets = NewSecretScanner()
+		if *secretsAllowlist != "" {
+			if err := original.Secrets.LoadAllowlist(*secretsAllowlist); err != nil {
+				return err
+			}
+		}
+	}
+
+	changes, originals, err := CompareTree(files, original)
+	if err != nil {
+		return err
+	}
+
+	contents := map[string][]byte{}
+	for _, file := range files {
+		contents[file.Path] = file.Content
+	}
+	if *showDiff {
+		for _, change := range changes {
+			if change.Status == StatusChanged {
+				UnifiedDiff(os.Stdout, "a/"+change.Path, "b/"+change.Path, originals[change.Path], contents[change.Path])
+			}
+		}
+	}
+	printChangeSummary(os.Stdout, changes)
+	return nil
+}
+
+// printChangeSummary lists every file that is not unchanged, then counts the statuses
+func printChangeSummary(w io.Writer, changes []FileChange) {
+	counts := map[ChangeStatus]int{}
+	fmt.Fprintf(w, "\n=== Unflatten Summary ===\n")
+	for _, change := range changes {
+		counts[change.Status]++
+		if change.Status == StatusUnchanged {
+			continue
+		}
+
+		note := ""
+		if change.EditedAfterFlatten {
+			note += " (edited in the combined file)"
+		}
+		if change.OriginalMoved {
+			note += " (original changed since the flatten)"
+		}
+		fmt.Fprintf(w, "  %-9s %s%s\n", change.Status, change.Path, note)
+	}
+
+	fmt.Fprintf(w, "\nUnchanged: %d\n", counts[StatusUnchanged])
+	fmt.Fprintf(w, "Changed:   %d\n", counts[StatusChanged])
+	fmt.Fprintf(w, "Added:     %d\n", counts[StatusAdded])
+	fmt.Fprintf(w, "Deleted:   %d\n", counts[StatusDeleted])
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Writer writes flattened files in one output format. The walk calls Begin once,
+// WriteFile for every selected file and End once.
+type Writer interface {
+	Begin() error
+	WriteFile(file SourceFile) error
+	End() error
+}
+
+// Output formats
+const (
+	FormatText     = "text"
+	FormatMarkdown = "markdown"
+	FormatJSON     = "json"
+	FormatXML      = "xml"
+)
+
+// formatExtensions are the default output file extensions of each format
+var formatExtensions = map[string]string{
+	FormatText:     ".txt",
+	FormatMarkdown: ".md",
+	FormatJSON:     ".json",
+	FormatXML:      ".xml",
+}
+
+// Formats returns the supported format names
+func Formats() []string {
+	formats := make([]string, 0, len(formatExtensions))
+	for format := range formatExtensions {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// ParseFormat checks a format name, accepting "md" and "txt" as short forms
+func ParseFormat(name string) (string, error) {
+	switch name = strings.ToLower(strings.TrimSpace(name)); name {
+	case "md":
+		return FormatMarkdown, nil
+	case "txt":
+		return FormatText, nil
+	}
+	if _, ok := formatExtensions[name]; !ok {
+		return "", fmt.Errorf("unknown format '%s', want one of %s", name, strings.Join(Formats(), ", "))
+	}
+	return name, nil
+}
+
+// FormatExtension returns the default output file extension of a format
+func FormatExtension(format string) string {
+	return formatExtensions[format]
+}
+
+// NewWriter creates a writer for the format
+func NewWriter(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatText:
+		return &TextWriter{w: w}, nil
+	case FormatMarkdown:
+		return &MarkdownWriter{w: w}, nil
+	case FormatJSON:
+		return &JSONWriter{w: w}, nil
+	case FormatXML:
+		return &XMLWriter{w: w}, nil
+	}
+	return nil, fmt.Errorf("unknown format '%s'", format)
+}
+
+// TextWriter writes every file's path as a comment followed by its contents
+type TextWriter struct {
+	w io.Writer
+}
+
+// Begin writes nothing
+func (tw *TextWriter) Begin() error {
+	return nil
+}
+
+// WriteFile writes the file's path as a comment followed by its contents. A path
+// that doesn't look like one to the parser, such as a top-level NOTES, gets a "./".
+func (tw *TextWriter) WriteFile(file SourceFile) error {
+	if err := checkHeaderPath(file.Path); err != nil {
+		return err
+	}
+	filePath := file.Path
+	if !looksLikePath(partSuffix.ReplaceAllString(filePath, "")) {
+		filePath = "./" + filePath
+	}
+	header := fmt.Sprintf("\n//%s\n\n", filePath)
+	if _, err := io.WriteString(tw.w, header); err != nil {
+		return fmt.Errorf("error writing header: %v", err)
+	}
+
+	// Write file contents
+	if _, err := tw.w.Write(file.Content); err != nil {
+		return fmt.Errorf("error writing content: %v", err)
+	}
+
+	// Add a newline after each file
+	if _, err := io.WriteString(tw.w, "\n"); err != nil {
+		return fmt.Errorf("error writing newline: %v", err)
+	}
+
+	return nil
+}
+
+// End writes nothing
+func (tw *TextWriter) End() error {
+	return nil
+}
+
+// checkHeaderPath rejects paths that can't be written on a header line and read back
+func checkHeaderPath(filePath string) error {
+	if strings.ContainsAny(filePath, "\r\n") || strings.TrimSpace(filePath) != filePath {
+		return fmt.Errorf("can't write the path %q on one header line; use -format json or xml", filePath)
+	}
+	return nil
+}
+
+// fileSHA256 returns the hex SHA-256 of a file's contents
+func fileSHA256(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// languageExtensions map file extensions to the language names used by Markdown code fences
+var languageExtensions = map[string]string{
+	".go": "go", ".mod": "go-mod",
+	".js": "javascript", ".mjs": "javascript", ".cjs": "javascript", ".jsx": "jsx",
+	".ts": "typescript", ".tsx": "tsx", ".vue": "vue", ".svelte": "svelte",
+	".py": "python", ".rb": "ruby", ".php": "php", ".pl": "perl", ".lua": "lua",
+	".java": "java", ".kt": "kotlin", ".kts": "kotlin", ".scala": "scala", ".groovy": "groovy",
+	".c": "c", ".h": "c", ".cc": "cpp", ".cpp": "cpp", ".hpp": "cpp", ".cs": "csharp",
+	".rs": "rust", ".swift": "swift", ".m": "objectivec", ".dart": "dart",
+	".sh": "bash", ".bash": "bash", ".zsh": "zsh", ".ps1": "powershell", ".bat": "batch",
+	".sql": "sql", ".graphql": "graphql", ".gql": "graphql", ".proto": "protobuf",
+	".html": "html", ".htm": "html", ".css": "css", ".scss": "scss", ".sass": "sass", ".less": "less",
+	".json": "json", ".yaml": "yaml", ".yml": "yaml", ".toml": "toml", ".ini": "ini", ".xml": "xml",
+	".md": "markdown", ".tex": "latex", ".r": "r", ".ex": "elixir", ".exs": "elixir", ".erl": "erlang",
+	".hs": "haskell", ".clj": "clojure", ".tf": "hcl", ".dockerfile": "dockerfile",
+}
+
+// languageNames map well-known file names without a telling extension
+var languageNames = map[string]string{
+	"Dockerfile":  "dockerfile",
+	"Makefile":    "makefile",
+	"makefile":    "makefile",
+	"Jenkinsfile": "groovy",
+	"Gemfile":     "ruby",
+	"Rakefile":    "ruby",
+}
+
+// DetectLanguage guesses a file's language from its name, or returns "" for plain text
+func DetectLanguage(filePath string) string {
+	name := path.Base(filePath)
+	if language, ok := languageNames[name]; ok {
+		return language
+	}
+	return languageExtensions[strings.ToLower(path.Ext(name))]
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// MarkdownWriter writes every file under a heading in a fenced code block tagged with its language
+type MarkdownWriter struct {
+	w io.Writer
+}
+
+// Begin writes nothing
+func (mw *MarkdownWriter) Begin() error {
+	return nil
+}
+
+// WriteFile writes the file's path as a heading and its contents in a code fence
+// longer than any run of backticks inside, so the block can't end early. The
+// heading notes a missing final newline, which the fence has to add.
+func (mw *MarkdownWriter) WriteFile(file SourceFile) error {
+	if err := checkHeaderPath(file.Path); err != nil {
+		return err
+	}
+	fence := strings.Repeat("`", max(3, longestRun(string(file.Content), '`')+1))
+	heading := file.Path
+	content := string(file.Content)
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		heading += noFinalNewline
+		content += "\n"
+	}
+
+	_, err := fmt.Fprintf(mw.w, "## %s\n\n%s%s\n%s%s\n\n", heading, fence, DetectLanguage(file.Path), content, fence)
+	if err != nil {
+		return fmt.Errorf("error writing file %s: %v", file.Path, err)
+	}
+	return nil
+}
+
+// End writes nothing
+func (mw *MarkdownWriter) End() error {
+	return nil
+}
+
+// longestRun returns the length of the longest run of c in text
+func longestRun(text string, c byte) int {
+	longest, run := 0, 0
+	for i := 0; i < len(text); i++ {
+		if text[i] == c {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	return longest
+}
+
+// FileRecord is a file as written by the JSON writer
+type FileRecord struct {
+	Path     string `json:"path"`
+	Language string `json:"language"`
+	Size     int64  `json:"size"`
+	SHA256   string `json:"sha256"`
+	Content  string `json:"content"`
+}
+
+// newFileRecord describes a source file for the structured formats
+func newFileRecord(file SourceFile) FileRecord {
+	return FileRecord{
+		Path:     file.Path,
+		Language: DetectLanguage(file.Path),
+		Size:     int64(len(file.Content)),
+		SHA256:   fileSHA256(file.Content),
+		Content:  string(file.Content),
+	}
+}
+
+// JSONWriter writes an array of FileRecord objects, one file at a time
+type JSONWriter struct {
+	w       io.Writer
+	written int
+}
+
+// Begin opens the array
+func (jw *JSONWriter) Begin() error {
+	_, err := io.WriteString(jw.w, "[")
+	return err
+}
+
+// WriteFile appends the file's record to the array
+func (jw *JSONWriter) WriteFile(file SourceFile) error {
+	data, err := json.MarshalIndent(newFileRecord(file), "  ", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding file %s: %v", file.Path, err)
+	}
+
+	separator := "\n  "
+	if jw.written > 0 {
+		separator = ",\n  "
+	}
+	if _, err := io.WriteString(jw.w, separator); err != nil {
+		return err
+	}
+	if _, err := jw.w.Write(data); err != nil {
+		return fmt.Errorf("error writing file %s: %v", file.Path, err)
+	}
+	jw.written++
+	return nil
+}
+
+// End closes the array
+func (jw *JSONWriter) End() error {
+	_, err := io.WriteString(jw.w, "\n]\n")
+	return err
+}
+
+// XMLWriter writes a <files> document with every file's contents in a CDATA section
+type XMLWriter struct {
+	w io.Writer
+}
+
+// Begin writes the XML declaration and opens the root element
+func (xw *XMLWriter) Begin() error {
+	_, err := io.WriteString(xw.w, xml.Header+"<files>\n")
+	return err
+}
+
+// WriteFile writes a <file> element with the metadata as attributes. Contents
+// XML can't carry as they are, such as control characters or carriage returns
+// (which parsers turn into newlines), are written in base64 with encoding="base64".
+func (xw *XMLWriter) WriteFile(file SourceFile) error {
+	record := newFileRecord(file)
+	var err error
+	if xmlSafe(record.Content) {
+		_, err = fmt.Fprintf(xw.w, "  <file path=\"%s\" language=\"%s\" size=\"%d\" sha256=\"%s\"><![CDATA[%s]]></file>\n",
+			xmlEscape(record.Path), record.Language, record.Size, record.SHA256, cdataEscape(record.Content))
+	} else {
+		_, err = fmt.Fprintf(xw.w, "  <file path=\"%s\" language=\"%s\" size=\"%d\" sha256=\"%s\" encoding=\"base64\">%s</file>\n",
+			xmlEscape(record.Path), record.Language, record.Size, record.SHA256, base64.StdEncoding.EncodeToString(file.Content))
+	}
+	if err != nil {
+		return fmt.Errorf("error writing file %s: %v", file.Path, err)
+	}
+	return nil
+}
+
+// End closes the root element
+func (xw *XMLWriter) End() error {
+	_, err := io.WriteString(xw.w, "</files>\n")
+	return err
+}
+
+// xmlEscape escapes text for an attribute value
+func xmlEscape(text string) string {
+	var escaped strings.Builder
+	xml.EscapeText(&escaped, []byte(text))
+	return escaped.String()
+}
+
+// xmlSafe reports whether text survives an XML document unchanged: valid UTF-8
+// made only of characters XML allows, and no carriage returns
+func xmlSafe(text string) bool {
+	if !utf8.ValidString(text) {
+		return false
+	}
+	for _, r := range text {
+		if (r < 0x20 && r != '\t' && r != '\n') || r == 0xFFFE || r == 0xFFFF {
+			return false
+		}
+	}
+	return true
+}
+
+// cdataEscape splits every "]]>" across two CDATA sections, since it would end the section
+func cdataEscape(text string) string {
+	return strings.ReplaceAll(text, "]]>", "]]]]><![CDATA[>")
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{"text", FormatText, false},
+		{"txt", FormatText, false},
+		{" Markdown ", FormatMarkdown, false},
+		{"md", FormatMarkdown, false},
+		{"JSON", FormatJSON, false},
+		{"xml", FormatXML, false},
+		{"yaml", "", true},
+	}
+	for _, test := range tests {
+		got, err := ParseFormat(test.name)
+		if (err != nil) != test.wantErr || got != test.want {
+			t.Errorf("ParseFormat(%q) = %q, %v, want %q", test.name, got, err, test.want)
+		}
+	}
+}
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"main.go", "go"},
+		{"src/App.TSX", "tsx"},
+		{"build/Dockerfile", "dockerfile"},
+		{"Makefile", "makefile"},
+		{"notes.txt", ""},
+		{"LICENSE", ""},
+	}
+	for _, test := range tests {
+		if got := DetectLanguage(test.path); got != test.want {
+			t.Errorf("DetectLanguage(%q) = %q, want %q", test.path, got, test.want)
+		}
+	}
+}
+
+// writeFiles writes the files in a format and returns the output
+func writeFiles(t *testing.T, format string, files []SourceFile) string {
+	t.Helper()
+	var output bytes.Buffer
+	writer, err := NewWriter(format, &output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Begin(); err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range files {
+		if err := writer.WriteFile(file); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.End(); err != nil {
+		t.Fatal(err)
+	}
+	return output.String()
+}
+
+// writerFiles are contents the structured formats must carry unchanged
+var writerFiles = []SourceFile{
+	{Path: "main.go", Content: []byte("package main\n")},
+	{Path: "docs/a & b.md", Content: []byte("```go\nx := 1\n```\n")},
+	{Path: "data.xml", Content: []byte("<![CDATA[ nested ]]> and ]]> again")},
+	{Path: "empty.txt", Content: []byte{}},
+}
+
+func TestJSONWriter(t *testing.T) {
+	var records []FileRecord
+	if err := json.Unmarshal([]byte(writeFiles(t, FormatJSON, writerFiles)), &records); err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != len(writerFiles) {
+		t.Fatalf("got %d records, want %d", len(records), len(writerFiles))
+	}
+	for i, record := range records {
+		file := writerFiles[i]
+		if record.Path != file.Path || record.Content != string(file.Content) || record.Size != int64(len(file.Content)) {
+			t.Errorf("record %d = %+v, want %s", i, record, file.Path)
+		}
+		if record.SHA256 != fileSHA256(file.Content) {
+			t.Errorf("record %d has sha256 %s", i, record.SHA256)
+		}
+	}
+	if got := writeFiles(t, FormatJSON, nil); got != "[\n]\n" {
+		t.Errorf("no files = %q, want an empty array", got)
+	}
+}
+
+func TestXMLWriter(t *testing.T) {
+	var document struct {
+		Files []struct {
+			Path     string `xml:"path,attr"`
+			Language string `xml:"language,attr"`
+			Content  string `xml:",chardata"`
+		} `xml:"file"`
+	}
+	if err := xml.Unmarshal([]byte(writeFiles(t, FormatXML, writerFiles)), &document); err != nil {
+		t.Fatal(err)
+	}
+	if len(document.Files) != len(writerFiles) {
+		t.Fatalf("got %d files, want %d", len(document.Files), len(writerFiles))
+	}
+	for i, file := range document.Files {
+		want := writerFiles[i]
+		if file.Path != want.Path || file.Language != DetectLanguage(want.Path) || file.Content != string(want.Content) {
+			t.Errorf("file %d = %+v, want %s with %q", i, file, want.Path, want.Content)
+		}
+	}
+}
+
+func TestXMLWriterBase64(t *testing.T) {
+	tests := []struct {
+		content string
+		base64  bool
+	}{
+		{"plain\ttext\n", false},
+		{"windows\r\nlines\r\n", true},
+		{"\x1b[31mred\x1b[0m", true},
+		{"\xff\xfe invalid UTF-8", true},
+	}
+	for _, test := range tests {
+		output := writeFiles(t, FormatXML, []SourceFile{{Path: "f.txt", Content: []byte(test.content)}})
+		if encoded := strings.Contains(output, `encoding="base64"`); encoded != test.base64 {
+			t.Errorf("%q written in base64 = %v, want %v", test.content, encoded, test.base64)
+		}
+		files, err := ParseCombined([]byte(output), FormatXML)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(files) != 1 || string(files[0].Content) != test.content {
+			t.Errorf("%q parsed back as %+v", test.content, files)
+		}
+	}
+}
+
+func TestMarkdownWriter(t *testing.T) {
+	tests := []struct {
+		content string
+		fence   string
+	}{
+		{"package main\n", "```go\n"},
+		{"```go\nx := 1\n```\n", "````go\n"},
+		{"a ````` run\n", "``````go\n"},
+	}
+	for _, test := range tests {
+		output := writeFiles(t, FormatMarkdown, []SourceFile{{Path: "main.go", Content: []byte(test.content)}})
+		if !strings.HasPrefix(output, "## main.go\n\n"+test.fence) {
+			t.Errorf("%q starts %q, want fence %q", test.content, output, test.fence)
+		}
+		fence := strings.TrimSuffix(strings.TrimSuffix(test.fence, "\n"), "go")
+		if !strings.HasSuffix(output, "\n"+fence+"\n\n") {
+			t.Errorf("%q ends %q, want fence %q", test.content, output, fence)
+		}
+	}
+}
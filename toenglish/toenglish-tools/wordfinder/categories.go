@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Category is a word cluster described in wordcategorizer/cluster_data.json
+type Category struct {
+	ID             int      `json:"cluster_id"`
+	Name           string   `json:"primary_name"`
+	AlternateNames []string `json:"alternate_names"`
+}
+
+// Label returns the category name used in the vocabulary file, e.g. "Cluster 3"
+func (c Category) Label() string {
+	return fmt.Sprintf("Cluster %d", c.ID)
+}
+
+// Categories lists the word clusters in file order
+type Categories []Category
+
+// LoadCategories reads the cluster names written by wordcategorizer
+func LoadCategories(path string) (Categories, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading category file: %v", err)
+	}
+
+	var file struct {
+		Clusters Categories `json:"clusters"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("error parsing category file: %v", err)
+	}
+
+	return file.Clusters, nil
+}
+
+// Find looks a category up by id, by label such as "Cluster 3", or by its primary
+// or an alternate name, ignoring case
+func (cs Categories) Find(query string) (Category, error) {
+	query = strings.TrimSpace(query)
+	id, idErr := strconv.Atoi(query)
+	for _, category := range cs {
+		if (idErr == nil && category.ID == id) || strings.EqualFold(category.Label(), query) ||
+			strings.EqualFold(category.Name, query) {
+			return category, nil
+		}
+		for _, name := range category.AlternateNames {
+			if strings.EqualFold(name, query) {
+				return category, nil
+			}
+		}
+	}
+	return Category{}, fmt.Errorf("unknown category '%s'", query)
+}
+
+// SelectWords returns the vocabulary entries in the category (empty label for all)
+// at the difficulty level (0 for all)
+func SelectWords(vocabulary *Vocabulary, label string, difficulty int) []VocabularyEntry {
+	selected := []VocabularyEntry{}
+	for _, entry := range vocabulary.Entries() {
+		if label != "" && entry.Category != label {
+			continue
+		}
+		if difficulty > 0 && entry.Difficulty != difficulty {
+			continue
+		}
+		selected = append(selected, entry)
+	}
+	return selected
+}
+
+// ClusterWords are the word groups written by wordcategorizer to clusters.json,
+// keyed by label such as "Cluster 3"
+type ClusterWords map[string][]string
+
+// LoadClusterWords reads the word groups of every cluster
+func LoadClusterWords(path string) (ClusterWords, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading cluster file: %v", err)
+	}
+
+	var clusters ClusterWords
+	if err := json.Unmarshal(data, &clusters); err != nil {
+		return nil, fmt.Errorf("error parsing cluster file: %v", err)
+	}
+
+	return clusters, nil
+}
+
+// Entries returns the words of a cluster (empty label for all) as vocabulary entries
+// without a difficulty, sorted by label so the result doesn't depend on map order
+func (cw ClusterWords) Entries(label string) []VocabularyEntry {
+	labels := make([]string, 0, len(cw))
+	for name := range cw {
+		if label == "" || strings.EqualFold(name, label) {
+			labels = append(labels, name)
+		}
+	}
+	sort.Strings(labels)
+
+	entries := []VocabularyEntry{}
+	for _, name := range labels {
+		for _, word := range cw[name] {
+			entries = append(entries, VocabularyEntry{Word: word, Category: name})
+		}
+	}
+	return entries
+}
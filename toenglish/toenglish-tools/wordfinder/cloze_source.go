@@ -0,0 +1,217 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// clozeBlank replaces every word of the answer in a cloze sentence
+const clozeBlank = "____"
+
+// WordDetailStore looks up the stored details of a word
+type WordDetailStore interface {
+	GetWordDetail(ctx context.Context, word string) (*VocabularyWordDetail, error)
+}
+
+// ClozeHintSource turns the stored example sentences into fill-in-the-blank hints
+type ClozeHintSource struct {
+	store      WordDetailStore
+	vocabulary *Vocabulary
+	leaks      *LeakDetector
+}
+
+// NewClozeHintSource creates a cloze source over the stored word details.
+// The vocabulary, used to find the simplest sentence, may be nil.
+func NewClozeHintSource(store WordDetailStore, vocabulary *Vocabulary) *ClozeHintSource {
+	return &ClozeHintSource{
+		store:      store,
+		vocabulary: vocabulary,
+		leaks:      NewLeakDetector(vocabulary, LeakStem),
+	}
+}
+
+// Name returns the name of the source
+func (cs *ClozeHintSource) Name() string {
+	return "cloze"
+}
+
+// Hints blanks the word out of its simplest example sentence, e.g.
+// "Fill the gap: She decided to ____ the sinking ship". Words without a
+// usable sentence get no hint.
+func (cs *ClozeHintSource) Hints(ctx context.Context, word string) ([]Hint, error) {
+	sentence, err := cs.sentence(ctx, word)
+	if err != nil || sentence == "" {
+		return []Hint{}, err
+	}
+	return []Hint{NewHint(HintCloze, cs.Name(), fmt.Sprintf("Fill the gap: %s", sentence))}, nil
+}
+
+// sentence returns the word's simplest cloze sentence, or "" when it has no usable one
+func (cs *ClozeHintSource) sentence(ctx context.Context, word string) (string, error) {
+	detail, err := cs.store.GetWordDetail(ctx, word)
+	if err != nil || detail == nil {
+		return "", err
+	}
+	sentence, _ := cs.simplestCloze(word, detail.ExampleSentences)
+	return sentence, nil
+}
+
+// Fallback wraps a source so that it is only queried for words without a
+// usable stored sentence. The wrapper keeps the source's name, so it can
+// still be picked or skipped per call.
+func (cs *ClozeHintSource) Fallback(source HintSource) HintSource {
+	return &clozeFallbackSource{cloze: cs, fallback: source}
+}
+
+// clozeFallbackSource queries its fallback source only when the cloze source has nothing
+type clozeFallbackSource struct {
+	cloze    *ClozeHintSource
+	fallback HintSource
+}
+
+// Name returns the name of the fallback source
+func (fs *clozeFallbackSource) Name() string {
+	return fs.fallback.Name()
+}
+
+// Hints returns no hints for words with a stored sentence and the fallback's
+// hints otherwise. A failed lookup counts as no sentence.
+func (fs *clozeFallbackSource) Hints(ctx context.Context, word string) ([]Hint, error) {
+	if sentence, err := fs.cloze.sentence(ctx, word); err == nil && sentence != "" {
+		return []Hint{}, nil
+	}
+	return fs.fallback.Hints(ctx, word)
+}
+
+// simplestCloze blanks the word out of every sentence and returns the one with the easiest vocabulary
+func (cs *ClozeHintSource) simplestCloze(word string, sentences []string) (string, bool) {
+	best, bestScore := "", 0.0
+	for _, sentence := range sentences {
+		cloze, ok := cs.blank(word, sentence)
+		if !ok {
+			continue
+		}
+		score := cs.simplicityScore(cloze)
+		if best == "" || score < bestScore || (score == bestScore && len(cloze) < len(best)) {
+			best, bestScore = cloze, score
+		}
+	}
+	return best, best != ""
+}
+
+// wordSpan is the byte range of one word inside a sentence
+type wordSpan struct {
+	start, end int
+	token      string
+}
+
+// sentenceSpans finds the words of a sentence together with their positions
+func sentenceSpans(sentence string) []wordSpan {
+	spans := []wordSpan{}
+	start := -1
+	for i, r := range sentence {
+		inWord := unicode.IsLetter(r) || r == '\''
+		if inWord && start < 0 {
+			start = i
+		}
+		if !inWord && start >= 0 {
+			spans = append(spans, wordSpan{start: start, end: i, token: strings.ToLower(sentence[start:i])})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		spans = append(spans, wordSpan{start: start, end: len(sentence), token: strings.ToLower(sentence[start:])})
+	}
+	return spans
+}
+
+// blank replaces the word, its inflections and its word family in the sentence.
+// Phrases are only blanked as a whole run of words. It reports false when the
+// sentence does not contain the word or still gives it away afterwards.
+func (cs *ClozeHintSource) blank(word, sentence string) (string, bool) {
+	spans := sentenceSpans(sentence)
+	parts := phraseTokens(word)
+	if len(parts) == 0 {
+		return "", false
+	}
+
+	strictness := LeakStem
+	if len(parts) > 1 {
+		strictness = LeakInflection
+	}
+	matchers := make([]*tokenMatcher, len(parts))
+	for i, part := range parts {
+		matchers[i] = cs.leaks.newTokenMatcher(part, strictness)
+	}
+
+	var builder strings.Builder
+	last, blanked := 0, false
+	for start := 0; start+len(parts) <= len(spans); start++ {
+		found := true
+		for i, matcher := range matchers {
+			if !matcher.matches(spans[start+i].token) {
+				found = false
+				break
+			}
+		}
+		if !found {
+			continue
+		}
+
+		end := start + len(parts) - 1
+		builder.WriteString(sentence[last:spans[start].start])
+		builder.WriteString(strings.TrimSpace(strings.Repeat(clozeBlank+" ", len(parts))))
+		last = spans[end].end
+		blanked = true
+		start = end
+	}
+	if !blanked {
+		return "", false
+	}
+	builder.WriteString(sentence[last:])
+
+	cloze := strings.TrimSpace(builder.String())
+	if cs.leaks.Leaks(word, cloze, LeakStem) {
+		return "", false
+	}
+	return cloze, true
+}
+
+// simplicityScore averages the difficulty of the sentence's content words; lower is simpler.
+// Words missing from the vocabulary count as harder than its hardest level, and
+// without a vocabulary the average word length stands in for difficulty.
+func (cs *ClozeHintSource) simplicityScore(sentence string) float64 {
+	total, count := 0.0, 0
+	for _, token := range tokenize(strings.ReplaceAll(sentence, clozeBlank, " ")) {
+		if phraseStopWords[token] {
+			continue
+		}
+		count++
+		if cs.vocabulary == nil {
+			total += float64(letterCount(token))
+			continue
+		}
+		total += float64(cs.tokenDifficulty(token))
+	}
+	if count == 0 {
+		return 0
+	}
+	return total / float64(count)
+}
+
+// tokenDifficulty looks up a word, or failing that the easiest member of its
+// word family so that "decided" ranks like "decide"
+func (cs *ClozeHintSource) tokenDifficulty(token string) int {
+	if entry, ok := cs.vocabulary.Lookup(token); ok && entry.Difficulty > 0 {
+		return entry.Difficulty
+	}
+	difficulty := 4
+	for _, member := range cs.vocabulary.Family(token) {
+		if entry, ok := cs.vocabulary.Lookup(member); ok && entry.Difficulty > 0 && entry.Difficulty < difficulty {
+			difficulty = entry.Difficulty
+		}
+	}
+	return difficulty
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// stubStore serves word details from a map
+type stubStore map[string]*VocabularyWordDetail
+
+func (ss stubStore) GetWordDetail(ctx context.Context, word string) (*VocabularyWordDetail, error) {
+	if word == "broken" {
+		return nil, errors.New("connection lost")
+	}
+	return ss[word], nil
+}
+
+func newTestStore() stubStore {
+	return stubStore{
+		"abandon": {ExampleSentences: []string{
+			"The crew had to abandon the ship.",
+			"They abandoned their car in the snow.",
+		}},
+		"give up": {ExampleSentences: []string{"Never give up on your dreams."}},
+		"run":     {ExampleSentences: []string{"She went for a walk."}},
+	}
+}
+
+func TestClozeHints(t *testing.T) {
+	cloze := NewClozeHintSource(newTestStore(), nil)
+	tests := []struct {
+		word string
+		want string
+	}{
+		{"abandon", "Fill the gap: The crew had to ____ the ship."},
+		{"give up", "Fill the gap: Never ____ ____ on your dreams."},
+
+		// No sentence contains the word, or there are no sentences at all
+		{"run", ""},
+		{"missing", ""},
+	}
+	for _, test := range tests {
+		hints, err := cloze.Hints(context.Background(), test.word)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := ""
+		if len(hints) > 0 {
+			got = hints[0].Text
+		}
+		if got != test.want {
+			t.Errorf("Hints(%q) = %q, want %q", test.word, got, test.want)
+		}
+	}
+
+	if _, err := cloze.Hints(context.Background(), "broken"); err == nil {
+		t.Error("expected the store error")
+	}
+}
+
+func TestClozeFallback(t *testing.T) {
+	tests := []struct {
+		word   string
+		called bool
+	}{
+		// Words covered by a stored sentence skip the fallback
+		{"abandon", false},
+		{"give up", false},
+
+		// Words without a usable sentence, or whose lookup fails, use it
+		{"run", true},
+		{"missing", true},
+		{"broken", true},
+	}
+	for _, test := range tests {
+		datamuse := &stubSource{name: "datamuse", hints: []Hint{NewHint(HintSynonym, "", "Similar to: quit")}}
+		source := NewClozeHintSource(newTestStore(), nil).Fallback(datamuse)
+		if source.Name() != "datamuse" {
+			t.Fatalf("Name() = %q, want the fallback's name", source.Name())
+		}
+
+		hints, err := source.Hints(context.Background(), test.word)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if called := datamuse.calls.Load() > 0; called != test.called || len(hints) > 0 != test.called {
+			t.Errorf("%q: fallback called %v with %d hints, want %v", test.word, called, len(hints), test.called)
+		}
+	}
+}
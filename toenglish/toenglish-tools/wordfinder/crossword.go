@@ -0,0 +1,495 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Crossword directions
+const (
+	Across = "across"
+	Down   = "down"
+)
+
+// CrosswordEntry is one answer of the puzzle together with its clue
+type CrosswordEntry struct {
+	Number    int      `json:"number"`
+	Direction string   `json:"direction"`
+	Row       int      `json:"row"`
+	Col       int      `json:"col"`
+	Length    int      `json:"length"`
+	Answer    string   `json:"answer"`
+	Clue      string   `json:"clue"`
+	ClueKind  HintKind `json:"clueKind"`
+}
+
+// Crossword is a solved puzzle layout. Grid rows use '#' for blocked cells.
+type Crossword struct {
+	Title      string           `json:"title"`
+	Category   string           `json:"category,omitempty"`
+	Difficulty int              `json:"difficulty,omitempty"`
+	Seed       int64            `json:"seed"`
+	Width      int              `json:"width"`
+	Height     int              `json:"height"`
+	Grid       []string         `json:"grid"`
+	Entries    []CrosswordEntry `json:"entries"`
+}
+
+// CrosswordOptions controls the puzzle generator
+type CrosswordOptions struct {
+	// MinWords and MaxWords bound the number of placed words
+	MinWords int
+	MaxWords int
+
+	// Size is the width and height of the grid the layout must fit in
+	Size int
+
+	// Seed makes the word order, and so the puzzle, reproducible
+	Seed int64
+
+	// MaxSteps bounds the backtracking search
+	MaxSteps int
+}
+
+// DefaultCrosswordOptions places 10 to 30 words on a 15x15 grid
+var DefaultCrosswordOptions = CrosswordOptions{
+	MinWords: 10,
+	MaxWords: 30,
+	Size:     15,
+	MaxSteps: 50000,
+}
+
+// crosswordClueKinds are the hint kinds used as clues, best first
+var crosswordClueKinds = []HintKind{HintDefinition, HintSynonym}
+
+// wordHintTimeout bounds the hint generation of one word of a puzzle or game,
+// so a slow or rate-limited DataMuse can't stall a whole run
+const wordHintTimeout = 5 * time.Second
+
+// hintBatchSize is the number of words whose hints the puzzle generators fetch at once
+const hintBatchSize = 4
+
+// forEachWordHints generates the hints of the words hintBatchSize at a time and
+// passes them to take in word order, until take returns false. Words whose hints
+// failed are passed a nil result.
+func forEachWordHints(ctx context.Context, hints *HintGenerator, words []string, take func(word string, result *HintResult) bool) error {
+	for start := 0; start < len(words); start += hintBatchSize {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		batch := words[start:min(start+hintBatchSize, len(words))]
+		results := make([]*HintResult, len(batch))
+		var wg sync.WaitGroup
+		for i, word := range batch {
+			wg.Add(1)
+			go func(i int, word string) {
+				defer wg.Done()
+				if result, err := hints.GenerateHints(ctx, word, HintOptions{Timeout: wordHintTimeout}); err == nil {
+					results[i] = result
+				}
+			}(i, word)
+		}
+		wg.Wait()
+
+		for i, word := range batch {
+			if !take(word, results[i]) {
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+// CrosswordGenerator builds puzzles from vocabulary words and their hints
+type CrosswordGenerator struct {
+	hints *HintGenerator
+}
+
+// NewCrosswordGenerator creates a generator that takes its clues from the hint generator
+func NewCrosswordGenerator(hints *HintGenerator) *CrosswordGenerator {
+	return &CrosswordGenerator{hints: hints}
+}
+
+// crosswordWord is a candidate answer with its clue
+type crosswordWord struct {
+	answer   string
+	clue     string
+	clueKind HintKind
+}
+
+// Generate lays out a puzzle from the entries. Words are tried in a seeded
+// random order; each needs a definition or synonym clue, and clues are only
+// fetched, a few words at a time, until there are enough candidates for the
+// layout solver.
+func (cg *CrosswordGenerator) Generate(ctx context.Context, entries []VocabularyEntry, opts CrosswordOptions) (*Crossword, error) {
+	if opts.MinWords <= 0 || opts.MaxWords < opts.MinWords || opts.Size < 3 {
+		return nil, fmt.Errorf("invalid crossword options: %d to %d words on a %dx%d grid", opts.MinWords, opts.MaxWords, opts.Size, opts.Size)
+	}
+	random := rand.New(rand.NewSource(opts.Seed))
+
+	answers := []string{}
+	seen := map[string]bool{}
+	for _, entry := range entries {
+		answer := crosswordAnswer(entry.Word, opts.Size)
+		if answer != "" && !seen[answer] {
+			seen[answer] = true
+			answers = append(answers, answer)
+		}
+	}
+	random.Shuffle(len(answers), func(i, j int) {
+		answers[i], answers[j] = answers[j], answers[i]
+	})
+
+	// Fetch clues for twice as many words as needed, leaving the solver room to choose,
+	// and give up after three times as many so an unreachable DataMuse can't stall the run
+	answers = answers[:min(len(answers), 3*opts.MaxWords)]
+	candidates := []crosswordWord{}
+	err := forEachWordHints(ctx, cg.hints, answers, func(answer string, result *HintResult) bool {
+		if clue, kind, ok := crosswordClue(result); ok {
+			candidates = append(candidates, crosswordWord{answer: answer, clue: clue, clueKind: kind})
+		}
+		return len(candidates) < 2*opts.MaxWords
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(candidates) < opts.MinWords {
+		return nil, fmt.Errorf("only %d of the %d words tried have a clue, need at least %d", len(candidates), len(answers), opts.MinWords)
+	}
+
+	// Long words first give the solver a backbone to cross
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return len(candidates[i].answer) > len(candidates[j].answer)
+	})
+
+	solver := newCrosswordSolver(candidates, opts)
+	placed := solver.solve()
+	if len(placed) < opts.MinWords {
+		return nil, fmt.Errorf("could only place %d words, need at least %d", len(placed), opts.MinWords)
+	}
+
+	crossword := buildCrossword(placed)
+	crossword.Seed = opts.Seed
+	return crossword, nil
+}
+
+// crosswordClue returns the best definition or synonym hint of a word's hints
+func crosswordClue(result *HintResult) (string, HintKind, bool) {
+	if result == nil {
+		return "", "", false
+	}
+	for _, kind := range crosswordClueKinds {
+		for _, hint := range result.Hints {
+			if hint.Kind == kind {
+				return hintContent(hint.Text), kind, true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// crosswordAnswer uppercases a word, or returns "" when it cannot be an answer
+// (phrases, punctuation, fewer than three letters or longer than the grid)
+func crosswordAnswer(word string, size int) string {
+	answer := strings.ToUpper(strings.TrimSpace(word))
+	if len(answer) < 3 || len(answer) > size {
+		return ""
+	}
+	for _, letter := range answer {
+		if letter < 'A' || letter > 'Z' {
+			return ""
+		}
+	}
+	return answer
+}
+
+// crosswordPlacement is a word at a position on the solver grid
+type crosswordPlacement struct {
+	word      crosswordWord
+	row, col  int
+	direction string
+	crossings int
+}
+
+// cell returns the grid position of the placement's i-th letter
+func (cp crosswordPlacement) cell(i int) (int, int) {
+	if cp.direction == Across {
+		return cp.row, cp.col + i
+	}
+	return cp.row + i, cp.col
+}
+
+// crosswordSolver places words by backtracking: every word is tried at each
+// position where it crosses the words already placed, or skipped.
+type crosswordSolver struct {
+	words   []crosswordWord
+	opts    CrosswordOptions
+	letters [][]byte
+	across  [][]bool
+	down    [][]bool
+	placed  []crosswordPlacement
+	best    []crosswordPlacement
+	steps   int
+}
+
+// newCrosswordSolver creates a solver over an empty grid
+func newCrosswordSolver(words []crosswordWord, opts CrosswordOptions) *crosswordSolver {
+	cs := &crosswordSolver{words: words, opts: opts}
+	cs.letters = make([][]byte, opts.Size)
+	cs.across = make([][]bool, opts.Size)
+	cs.down = make([][]bool, opts.Size)
+	for i := range cs.letters {
+		cs.letters[i] = make([]byte, opts.Size)
+		cs.across[i] = make([]bool, opts.Size)
+		cs.down[i] = make([]bool, opts.Size)
+	}
+	return cs
+}
+
+// solve returns the largest layout found within the step budget
+func (cs *crosswordSolver) solve() []crosswordPlacement {
+	// Try each of the first few words as the centred backbone
+	for start := 0; start < len(cs.words) && start < 5; start++ {
+		word := cs.words[start]
+		first := crosswordPlacement{
+			word:      word,
+			row:       cs.opts.Size / 2,
+			col:       (cs.opts.Size - len(word.answer)) / 2,
+			direction: Across,
+		}
+		cs.place(first)
+		done := cs.search(0, start)
+		cs.remove(first)
+		if done || cs.steps > cs.opts.MaxSteps {
+			break
+		}
+	}
+	return cs.best
+}
+
+// search tries to place the words from index next onward, skipping the backbone word.
+// It reports true once MaxWords are placed or the step budget is spent.
+func (cs *crosswordSolver) search(next, backbone int) bool {
+	if len(cs.placed) > len(cs.best) {
+		cs.best = append([]crosswordPlacement(nil), cs.placed...)
+	}
+	if len(cs.placed) >= cs.opts.MaxWords {
+		return true
+	}
+	cs.steps++
+	if cs.steps > cs.opts.MaxSteps || next >= len(cs.words) {
+		return cs.steps > cs.opts.MaxSteps
+	}
+	// Not enough words left to beat the best layout
+	if len(cs.placed)+len(cs.words)-next <= len(cs.best) {
+		return false
+	}
+	if next == backbone {
+		return cs.search(next+1, backbone)
+	}
+
+	// Positions with more crossings make denser puzzles, so they are tried first
+	placements := cs.placements(cs.words[next])
+	sort.SliceStable(placements, func(i, j int) bool {
+		return placements[i].crossings > placements[j].crossings
+	})
+	if len(placements) > 6 {
+		placements = placements[:6]
+	}
+	for _, placement := range placements {
+		cs.place(placement)
+		done := cs.search(next+1, backbone)
+		cs.remove(placement)
+		if done {
+			return true
+		}
+	}
+
+	return cs.search(next+1, backbone)
+}
+
+// placements lists every valid position of the word that crosses the current layout
+func (cs *crosswordSolver) placements(word crosswordWord) []crosswordPlacement {
+	placements := []crosswordPlacement{}
+	n := len(word.answer)
+	for row := 0; row < cs.opts.Size; row++ {
+		for col := 0; col < cs.opts.Size; col++ {
+			for _, direction := range []string{Across, Down} {
+				if direction == Across && col+n > cs.opts.Size || direction == Down && row+n > cs.opts.Size {
+					continue
+				}
+				placement := crosswordPlacement{word: word, row: row, col: col, direction: direction}
+				if crossings, ok := cs.fits(placement); ok {
+					placement.crossings = crossings
+					placements = append(placements, placement)
+				}
+			}
+		}
+	}
+	return placements
+}
+
+// fits checks the placement against the layout and counts its crossings.
+// New letters may not touch other words side by side, and the cells just
+// before and after the word must stay empty.
+func (cs *crosswordSolver) fits(p crosswordPlacement) (int, bool) {
+	n := len(p.word.answer)
+	beforeRow, beforeCol := p.cell(-1)
+	afterRow, afterCol := p.cell(n)
+	if cs.letterAt(beforeRow, beforeCol) != 0 || cs.letterAt(afterRow, afterCol) != 0 {
+		return 0, false
+	}
+
+	crossings := 0
+	for i := 0; i < n; i++ {
+		row, col := p.cell(i)
+		existing := cs.letters[row][col]
+		if existing != 0 {
+			if existing != p.word.answer[i] || cs.usedIn(row, col, p.direction) {
+				return 0, false
+			}
+			crossings++
+			continue
+		}
+
+		// Side neighbours of a new letter would form unintended words
+		var side1, side2 byte
+		if p.direction == Across {
+			side1, side2 = cs.letterAt(row-1, col), cs.letterAt(row+1, col)
+		} else {
+			side1, side2 = cs.letterAt(row, col-1), cs.letterAt(row, col+1)
+		}
+		if side1 != 0 || side2 != 0 {
+			return 0, false
+		}
+	}
+
+	if crossings == 0 || crossings == n {
+		return 0, false
+	}
+	return crossings, true
+}
+
+// letterAt returns the letter at a cell, or 0 for empty and out-of-grid cells
+func (cs *crosswordSolver) letterAt(row, col int) byte {
+	if row < 0 || col < 0 || row >= cs.opts.Size || col >= cs.opts.Size {
+		return 0
+	}
+	return cs.letters[row][col]
+}
+
+// usedIn reports whether a word in the direction already covers the cell
+func (cs *crosswordSolver) usedIn(row, col int, direction string) bool {
+	if direction == Across {
+		return cs.across[row][col]
+	}
+	return cs.down[row][col]
+}
+
+// place writes the word onto the grid
+func (cs *crosswordSolver) place(p crosswordPlacement) {
+	for i := 0; i < len(p.word.answer); i++ {
+		row, col := p.cell(i)
+		cs.letters[row][col] = p.word.answer[i]
+		if p.direction == Across {
+			cs.across[row][col] = true
+		} else {
+			cs.down[row][col] = true
+		}
+	}
+	cs.placed = append(cs.placed, p)
+}
+
+// remove takes the last placed word off the grid, keeping letters shared with crossing words
+func (cs *crosswordSolver) remove(p crosswordPlacement) {
+	for i := 0; i < len(p.word.answer); i++ {
+		row, col := p.cell(i)
+		if p.direction == Across {
+			cs.across[row][col] = false
+		} else {
+			cs.down[row][col] = false
+		}
+		if !cs.across[row][col] && !cs.down[row][col] {
+			cs.letters[row][col] = 0
+		}
+	}
+	cs.placed = cs.placed[:len(cs.placed)-1]
+}
+
+// buildCrossword crops the layout to its bounding box and numbers the entries
+func buildCrossword(placed []crosswordPlacement) *Crossword {
+	minRow, minCol, maxRow, maxCol := -1, -1, -1, -1
+	for _, p := range placed {
+		endRow, endCol := p.cell(len(p.word.answer) - 1)
+		if minRow < 0 || p.row < minRow {
+			minRow = p.row
+		}
+		if minCol < 0 || p.col < minCol {
+			minCol = p.col
+		}
+		if endRow > maxRow {
+			maxRow = endRow
+		}
+		if endCol > maxCol {
+			maxCol = endCol
+		}
+	}
+
+	crossword := &Crossword{Width: maxCol - minCol + 1, Height: maxRow - minRow + 1}
+	grid := make([][]byte, crossword.Height)
+	for i := range grid {
+		grid[i] = []byte(strings.Repeat("#", crossword.Width))
+	}
+	for _, p := range placed {
+		p.row -= minRow
+		p.col -= minCol
+		for i := 0; i < len(p.word.answer); i++ {
+			row, col := p.cell(i)
+			grid[row][col] = p.word.answer[i]
+		}
+	}
+	for _, row := range grid {
+		crossword.Grid = append(crossword.Grid, string(row))
+	}
+
+	// Number entries in reading order; across and down entries starting in the same cell share a number
+	sort.SliceStable(placed, func(i, j int) bool {
+		if placed[i].row != placed[j].row {
+			return placed[i].row < placed[j].row
+		}
+		return placed[i].col < placed[j].col
+	})
+	numbers := map[[2]int]int{}
+	for _, p := range placed {
+		start := [2]int{p.row - minRow, p.col - minCol}
+		number, ok := numbers[start]
+		if !ok {
+			number = len(numbers) + 1
+			numbers[start] = number
+		}
+		crossword.Entries = append(crossword.Entries, CrosswordEntry{
+			Number:    number,
+			Direction: p.direction,
+			Row:       start[0],
+			Col:       start[1],
+			Length:    len(p.word.answer),
+			Answer:    p.word.answer,
+			Clue:      p.word.clue,
+			ClueKind:  p.word.clueKind,
+		})
+	}
+	sort.SliceStable(crossword.Entries, func(i, j int) bool {
+		if crossword.Entries[i].Direction != crossword.Entries[j].Direction {
+			return crossword.Entries[i].Direction == Across
+		}
+		return crossword.Entries[i].Number < crossword.Entries[j].Number
+	})
+
+	return crossword
+}
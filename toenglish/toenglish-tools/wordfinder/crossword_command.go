@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+)
+
+// runCrosswordCommand generates a crossword for a category or difficulty level
+// and writes it as JSON for the app and as printable HTML
+func runCrosswordCommand(args []string) error {
+	fs := flag.NewFlagSet("crossword", flag.ExitOnError)
+	minWords := fs.Int("min-words", DefaultCrosswordOptions.MinWords, "fewest words to place")
+	maxWords := fs.Int("max-words", DefaultCrosswordOptions.MaxWords, "most words to place")
+	size := fs.Int("size", DefaultCrosswordOptions.Size, "width and height of the grid")
+	wordFlags := addPuzzleWordFlags(fs, "crossword")
+	setupFlags := addHintSetupFlags(fs)
+	fs.Parse(args)
+
+	setup, err := setupFlags.build()
+	if err != nil {
+		return err
+	}
+	defer setup.Close()
+
+	words, err := wordFlags.selectEntries("Crossword", setup.vocabulary)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Building a crossword from %d words...\n", len(words.entries))
+
+	opts := DefaultCrosswordOptions
+	opts.MinWords = *minWords
+	opts.MaxWords = *maxWords
+	opts.Size = *size
+	opts.Seed = *wordFlags.seed
+
+	crossword, err := NewCrosswordGenerator(setup.generator).Generate(context.Background(), words.entries, opts)
+	if err != nil {
+		return err
+	}
+	crossword.Title = words.title
+	crossword.Category = words.label
+	crossword.Difficulty = *wordFlags.difficulty
+
+	render := func(w io.Writer, showAnswers bool) error {
+		return RenderCrosswordHTML(w, crossword, showAnswers)
+	}
+	if err := wordFlags.writePuzzle(crossword, render); err != nil {
+		return err
+	}
+
+	fmt.Printf("Placed %d words on a %dx%d grid (seed %d), written to %s.json and %s.html\n",
+		len(crossword.Entries), crossword.Width, crossword.Height, crossword.Seed, *wordFlags.out, *wordFlags.out)
+	return nil
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+)
+
+// crosswordCellSize is the side of one grid cell in the SVG, in pixels
+const crosswordCellSize = 36
+
+// crosswordTemplate renders a printable page with the SVG grid and both clue lists
+var crosswordTemplate = template.Must(template.New("crossword").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+  body { font-family: Georgia, serif; margin: 2em; color: #222; }
+  h1 { font-size: 1.6em; margin-bottom: 0.2em; }
+  .meta { color: #666; margin-bottom: 1.5em; }
+  .clues { display: flex; gap: 3em; margin-top: 1.5em; }
+  .clues ol { padding-left: 0; list-style: none; }
+  .clues li { margin-bottom: 0.4em; }
+  .clues b { display: inline-block; min-width: 2em; }
+  @media print { body { margin: 0.5in; } }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<div class="meta">{{if .Category}}{{.Category}} · {{end}}{{if .Difficulty}}Difficulty {{.Difficulty}} · {{end}}{{len .Entries}} words</div>
+{{.SVG}}
+<div class="clues">
+  <div><h2>Across</h2><ol>{{range .Across}}<li><b>{{.Number}}.</b> {{.Clue}} ({{.Length}})</li>{{end}}</ol></div>
+  <div><h2>Down</h2><ol>{{range .Down}}<li><b>{{.Number}}.</b> {{.Clue}} ({{.Length}})</li>{{end}}</ol></div>
+</div>
+</body>
+</html>
+`))
+
+// crosswordPage is the data passed to crosswordTemplate
+type crosswordPage struct {
+	*Crossword
+	SVG    template.HTML
+	Across []CrosswordEntry
+	Down   []CrosswordEntry
+}
+
+// RenderCrosswordHTML writes a printable HTML page of the puzzle.
+// With showAnswers the grid is filled in, for an answer key.
+func RenderCrosswordHTML(w io.Writer, crossword *Crossword, showAnswers bool) error {
+	page := crosswordPage{
+		Crossword: crossword,
+		SVG:       template.HTML(RenderCrosswordSVG(crossword, showAnswers)),
+	}
+	for _, entry := range crossword.Entries {
+		if entry.Direction == Across {
+			page.Across = append(page.Across, entry)
+		} else {
+			page.Down = append(page.Down, entry)
+		}
+	}
+
+	if err := crosswordTemplate.Execute(w, page); err != nil {
+		return fmt.Errorf("error rendering crossword: %v", err)
+	}
+	return nil
+}
+
+// RenderCrosswordSVG draws the grid with entry numbers and, optionally, the answers
+func RenderCrosswordSVG(crossword *Crossword, showAnswers bool) string {
+	size := crosswordCellSize
+	width, height := crossword.Width*size+2, crossword.Height*size+2
+
+	numbers := map[[2]int]int{}
+	for _, entry := range crossword.Entries {
+		numbers[[2]int{entry.Row, entry.Col}] = entry.Number
+	}
+
+	var svg strings.Builder
+	fmt.Fprintf(&svg, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`, width, height, width, height)
+	for row, line := range crossword.Grid {
+		for col := 0; col < len(line); col++ {
+			if line[col] == '#' {
+				continue
+			}
+			x, y := col*size+1, row*size+1
+			fmt.Fprintf(&svg, `<rect x="%d" y="%d" width="%d" height="%d" fill="#fff" stroke="#222" stroke-width="1.5"/>`, x, y, size, size)
+			if number, ok := numbers[[2]int{row, col}]; ok {
+				fmt.Fprintf(&svg, `<text x="%d" y="%d" font-size="10" font-family="sans-serif">%d</text>`, x+3, y+11, number)
+			}
+			if showAnswers {
+				fmt.Fprintf(&svg, `<text x="%d" y="%d" font-size="20" font-family="sans-serif" text-anchor="middle">%c</text>`, x+size/2, y+size-9, line[col])
+			}
+		}
+	}
+	svg.WriteString("</svg>")
+	return svg.String()
+}
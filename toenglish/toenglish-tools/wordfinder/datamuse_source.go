@@ -0,0 +1,251 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DataMuseWord represents a single word response from DataMuse API
+type DataMuseWord struct {
+	Word  string   `json:"word"`
+	Score int      `json:"score"`
+	Tags  []string `json:"tags,omitempty"`
+	Defs  []string `json:"defs,omitempty"`
+}
+
+// DataMuseHintSource generates hints using the DataMuse API
+type DataMuseHintSource struct {
+	httpClient *HTTPClient
+	baseURL    string
+	cache      HintCache
+}
+
+// NewDataMuseHintSource creates a new DataMuse hint source with proper timeout,
+// retries and a rate limit well below DataMuse's fair-use allowance.
+// Responses are read from and written to cache when it is not nil.
+func NewDataMuseHintSource(cache HintCache) *DataMuseHintSource {
+	return &DataMuseHintSource{
+		httpClient: NewHTTPClient(5*time.Second, 10),
+		baseURL:    "https://api.datamuse.com/words",
+		cache:      cache,
+	}
+}
+
+// Name returns the name of the source
+func (ds *DataMuseHintSource) Name() string {
+	return "datamuse"
+}
+
+// Hints collects definition, synonym, "sounds like" and related word hints.
+// The four DataMuse queries run concurrently and their hints are merged in
+// that fixed order; any partial hints are returned together with the errors.
+func (ds *DataMuseHintSource) Hints(ctx context.Context, word string) ([]Hint, error) {
+	getters := []func(context.Context, string) ([]Hint, error){
+		ds.getDefinitionHints,
+		ds.getSynonymHints,
+		ds.getSoundsLikeHints,
+		ds.getRelatedWordHints,
+	}
+
+	results := make([][]Hint, len(getters))
+	errs := make([]error, len(getters))
+
+	var wg sync.WaitGroup
+	for i, get := range getters {
+		wg.Add(1)
+		go func(i int, get func(context.Context, string) ([]Hint, error)) {
+			defer wg.Done()
+			results[i], errs[i] = get(ctx, word)
+		}(i, get)
+	}
+	wg.Wait()
+
+	hints := []Hint{}
+	for _, h := range results {
+		hints = append(hints, h...)
+	}
+
+	return hints, errors.Join(errs...)
+}
+
+// relationQuery builds the DataMuse query parameters for a relation.
+// Phrases use DataMuse's phrase-friendly parameters: "means like" instead of
+// strict synonyms, and triggers of the phrase's head word for related words.
+func relationQuery(relation, word string) url.Values {
+	phrase := isPhrase(word)
+	switch relation {
+	case RelationDefinition:
+		return url.Values{"sp": {word}, "md": {"d"}}
+	case RelationSynonym:
+		if phrase {
+			return url.Values{"ml": {word}, "max": {"10"}}
+		}
+		return url.Values{"rel_syn": {word}}
+	case RelationSoundsLike:
+		return url.Values{"sl": {word}}
+	case RelationRelated:
+		if phrase {
+			return url.Values{"rel_trg": {headToken(word)}}
+		}
+		return url.Values{"rel_trg": {word}}
+	}
+	return url.Values{}
+}
+
+// fetch returns the DataMuse words for the relation, using the cache when available
+func (ds *DataMuseHintSource) fetch(ctx context.Context, relation, word string) ([]DataMuseWord, error) {
+	if ds.cache != nil {
+		if words, ok := ds.cache.Get(relation, word); ok {
+			return words, nil
+		}
+	}
+	return ds.fetchAndStore(ctx, relation, word)
+}
+
+// fetchAndStore queries DataMuse for the relation and stores the response in the cache
+func (ds *DataMuseHintSource) fetchAndStore(ctx context.Context, relation, word string) ([]DataMuseWord, error) {
+	words, err := ds.query(ctx, relationQuery(relation, word))
+	if err != nil {
+		return nil, err
+	}
+
+	if ds.cache != nil {
+		// A failed cache write should not cost us the response we already have
+		if err := ds.cache.Set(relation, word, words); err != nil {
+			log.Printf("Warning: failed to cache %s for '%s': %v", relation, word, err)
+		}
+	}
+	return words, nil
+}
+
+// Prefetch loads every cached relation for the word into the cache.
+// It returns the number of relations that had to be fetched from DataMuse.
+func (ds *DataMuseHintSource) Prefetch(ctx context.Context, word string) (int, error) {
+	fetched := 0
+	for _, relation := range []string{RelationDefinition, RelationSynonym, RelationSoundsLike, RelationRelated} {
+		if ds.cache != nil {
+			if _, ok := ds.cache.Get(relation, word); ok {
+				continue
+			}
+		}
+		if _, err := ds.fetchAndStore(ctx, relation, word); err != nil {
+			return fetched, fmt.Errorf("failed to fetch %s for '%s': %v", relation, word, err)
+		}
+		fetched++
+	}
+	return fetched, nil
+}
+
+// query calls the DataMuse words endpoint with the given query parameters
+func (ds *DataMuseHintSource) query(ctx context.Context, params url.Values) ([]DataMuseWord, error) {
+	requestURL := fmt.Sprintf("%s?%s", ds.baseURL, params.Encode())
+
+	var words []DataMuseWord
+	if err := ds.httpClient.GetJSON(ctx, requestURL, &words); err != nil {
+		return nil, err
+	}
+	return words, nil
+}
+
+// getDefinitionHints gets definition-based hints
+func (ds *DataMuseHintSource) getDefinitionHints(ctx context.Context, word string) ([]Hint, error) {
+	words, err := ds.fetch(ctx, RelationDefinition, word)
+	if err != nil {
+		return nil, err
+	}
+
+	hints := []Hint{}
+	wordLower := strings.ToLower(word)
+
+	// Process definition hints
+	for _, w := range words {
+		if strings.ToLower(w.Word) == wordLower && len(w.Defs) > 0 {
+			for _, def := range w.Defs {
+				// Clean the definition (remove part of speech prefix like "n\t")
+				parts := strings.SplitN(def, "\t", 2)
+				if len(parts) == 2 {
+					cleanDef := parts[1]
+					// Check that the definition doesn't contain the original word
+					if !strings.Contains(strings.ToLower(cleanDef), wordLower) {
+						hints = append(hints, NewHint(HintDefinition, ds.Name(), fmt.Sprintf("Definition: %s", cleanDef)))
+						if len(hints) >= 2 {
+							break
+						}
+					}
+				}
+			}
+			break
+		}
+	}
+
+	return hints, nil
+}
+
+// getSynonymHints gets synonym-based hints
+func (ds *DataMuseHintSource) getSynonymHints(ctx context.Context, word string) ([]Hint, error) {
+	words, err := ds.fetch(ctx, RelationSynonym, word)
+	if err != nil {
+		return nil, err
+	}
+
+	hints := []Hint{}
+
+	// Get top 2 synonyms as hints
+	for i, w := range words {
+		if i >= 2 || len(hints) >= 2 {
+			break
+		}
+		hints = append(hints, NewHint(HintSynonym, ds.Name(), fmt.Sprintf("Similar to: %s", w.Word)))
+	}
+
+	return hints, nil
+}
+
+// getSoundsLikeHints gets "sounds like" hints
+func (ds *DataMuseHintSource) getSoundsLikeHints(ctx context.Context, word string) ([]Hint, error) {
+	words, err := ds.fetch(ctx, RelationSoundsLike, word)
+	if err != nil {
+		return nil, err
+	}
+
+	hints := []Hint{}
+
+	// Only add "sounds like" if it's not too similar
+	for i, w := range words {
+		if i >= 2 || len(hints) >= 1 {
+			break
+		}
+		// Avoid returning words that are very close to original
+		if letterCount(w.Word) > 3 && w.Word != word {
+			hints = append(hints, NewHint(HintSoundsLike, ds.Name(), fmt.Sprintf("Sounds like: %s", w.Word)))
+		}
+	}
+
+	return hints, nil
+}
+
+// getRelatedWordHints gets related word hints
+func (ds *DataMuseHintSource) getRelatedWordHints(ctx context.Context, word string) ([]Hint, error) {
+	words, err := ds.fetch(ctx, RelationRelated, word)
+	if err != nil {
+		return nil, err
+	}
+
+	hints := []Hint{}
+
+	// Get a couple related words
+	for i, w := range words {
+		if i >= 3 || len(hints) >= 2 {
+			break
+		}
+		hints = append(hints, NewHint(HintRelated, ds.Name(), fmt.Sprintf("Think of: %s", w.Word)))
+	}
+
+	return hints, nil
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DictionaryResponse represents a single entry returned by the dictionary API
+type DictionaryResponse struct {
+	Word      string `json:"word"`
+	Phonetic  string `json:"phonetic,omitempty"`
+	Phonetics []struct {
+		Text  string `json:"text,omitempty"`
+		Audio string `json:"audio,omitempty"`
+	} `json:"phonetics,omitempty"`
+	Meanings []struct {
+		PartOfSpeech string `json:"partOfSpeech,omitempty"`
+		Definitions  []struct {
+			Definition string   `json:"definition,omitempty"`
+			Example    string   `json:"example,omitempty"`
+			Synonyms   []string `json:"synonyms,omitempty"`
+			Antonyms   []string `json:"antonyms,omitempty"`
+		} `json:"definitions,omitempty"`
+	} `json:"meanings,omitempty"`
+}
+
+// DictionaryAPIChecker checks words against the free dictionaryapi.dev service
+type DictionaryAPIChecker struct {
+	httpClient *HTTPClient
+	baseURL    string
+}
+
+// NewDictionaryAPIChecker creates a new dictionary API checker with proper timeout
+func NewDictionaryAPIChecker() *DictionaryAPIChecker {
+	return &DictionaryAPIChecker{
+		httpClient: NewHTTPClient(5*time.Second, 5),
+		baseURL:    "https://api.dictionaryapi.dev/api/v2/entries/en/",
+	}
+}
+
+// Name returns the name of the checker
+func (dc *DictionaryAPIChecker) Name() string {
+	return "dictionaryapi"
+}
+
+// Check looks the word up; 200 means the word exists and 404 means it doesn't
+func (dc *DictionaryAPIChecker) Check(ctx context.Context, word string) (CheckResult, error) {
+	requestURL := dc.baseURL + url.PathEscape(strings.TrimSpace(strings.ToLower(word)))
+
+	var entries []DictionaryResponse
+	if err := dc.httpClient.GetJSON(ctx, requestURL, &entries); err != nil {
+		if IsHTTPErrorKind(err, HTTPErrorNotFound) {
+			return CheckResult{Checker: dc.Name(), Verdict: VerdictInvalid, Confidence: 0.9}, nil
+		}
+		return CheckResult{}, err
+	}
+
+	// Collect the parts of speech of every meaning
+	tags := []string{}
+	seen := map[string]bool{}
+	for _, entry := range entries {
+		for _, meaning := range entry.Meanings {
+			if meaning.PartOfSpeech != "" && !seen[meaning.PartOfSpeech] {
+				seen[meaning.PartOfSpeech] = true
+				tags = append(tags, meaning.PartOfSpeech)
+			}
+		}
+	}
+
+	return CheckResult{Checker: dc.Name(), Verdict: VerdictValid, Confidence: 0.95, POSTags: tags}, nil
+}
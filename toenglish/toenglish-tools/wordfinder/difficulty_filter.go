@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// difficultyCheckedKinds lists the hint kinds whose vocabulary must suit the learner
+var difficultyCheckedKinds = []HintKind{HintSynonym, HintRelated}
+
+// difficultyBucket groups hint words by how well they fit the target's difficulty
+type difficultyBucket int
+
+const (
+	bucketAllowed difficultyBucket = iota
+	bucketOutOfList
+	bucketTooHard
+)
+
+// DifficultyFilter keeps synonym and related hints at or below the target word's difficulty
+type DifficultyFilter struct {
+	vocabulary *Vocabulary
+}
+
+// NewDifficultyFilter creates a filter backed by the categorized vocabulary
+func NewDifficultyFilter(vocabulary *Vocabulary) *DifficultyFilter {
+	return &DifficultyFilter{vocabulary: vocabulary}
+}
+
+// TargetDifficulty returns the difficulty level of the word, or 0 when it is not in the list
+func (df *DifficultyFilter) TargetDifficulty(word string) int {
+	entry, ok := df.vocabulary.Lookup(word)
+	if !ok {
+		return 0
+	}
+	return entry.Difficulty
+}
+
+// rankedHint is a hint together with the data used to rank it
+type rankedHint struct {
+	hint       Hint
+	bucket     difficultyBucket
+	difficulty int
+}
+
+// Filter drops synonym and related hints that use words harder than the target.
+// Words missing from the vocabulary are ranked after known words. When a
+// kind has no hint at or below the target difficulty, the filter falls back
+// to out-of-list words or, failing that, to harder words, and describes the
+// fallback in the returned notes. A target of 0 looks the difficulty up from word.
+func (df *DifficultyFilter) Filter(word string, target int, hints []Hint) ([]Hint, []string) {
+	if target <= 0 {
+		target = df.TargetDifficulty(word)
+	}
+	if target <= 0 {
+		return hints, nil
+	}
+
+	filtered := make([]Hint, len(hints))
+	copy(filtered, hints)
+	notes := []string{}
+
+	for _, kind := range difficultyCheckedKinds {
+		positions := []int{}
+		ranked := []rankedHint{}
+		for i, hint := range filtered {
+			if hint.Kind != kind {
+				continue
+			}
+			positions = append(positions, i)
+			ranked = append(ranked, df.rank(hint, target))
+		}
+		if len(ranked) == 0 {
+			continue
+		}
+
+		sort.SliceStable(ranked, func(i, j int) bool {
+			if ranked[i].bucket != ranked[j].bucket {
+				return ranked[i].bucket < ranked[j].bucket
+			}
+			return ranked[i].difficulty < ranked[j].difficulty
+		})
+
+		// Harder words are only kept when nothing else is available
+		best := ranked[0].bucket
+		kept := ranked[:0]
+		for _, r := range ranked {
+			if r.bucket != bucketTooHard || best == bucketTooHard {
+				kept = append(kept, r)
+			}
+		}
+
+		switch best {
+		case bucketOutOfList:
+			notes = append(notes, fmt.Sprintf("%s: no hint words at or below difficulty %d, used words outside the vocabulary", kind, target))
+		case bucketTooHard:
+			notes = append(notes, fmt.Sprintf("%s: no hint words at or below difficulty %d, used harder words", kind, target))
+		}
+
+		// Put the kept hints back into the slots this kind occupied, marking the rest for removal
+		for i, pos := range positions {
+			if i < len(kept) {
+				filtered[pos] = kept[i].hint
+			} else {
+				filtered[pos] = Hint{}
+			}
+		}
+	}
+
+	result := filtered[:0]
+	for _, hint := range filtered {
+		if hint.Kind != "" {
+			result = append(result, hint)
+		}
+	}
+	return result, notes
+}
+
+// rank places a hint into a difficulty bucket
+func (df *DifficultyFilter) rank(hint Hint, target int) rankedHint {
+	hintWord := strings.ToLower(strings.TrimSpace(hintContent(hint.Text)))
+	entry, ok := df.vocabulary.Lookup(hintWord)
+	if !ok {
+		return rankedHint{hint: hint, bucket: bucketOutOfList}
+	}
+	if entry.Difficulty > target {
+		return rankedHint{hint: hint, bucket: bucketTooHard, difficulty: entry.Difficulty}
+	}
+	return rankedHint{hint: hint, bucket: bucketAllowed, difficulty: entry.Difficulty}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func newTestDifficultyFilter() *DifficultyFilter {
+	return NewDifficultyFilter(NewVocabulary([]VocabularyEntry{
+		{Word: "leave", Difficulty: 1},
+		{Word: "quit", Difficulty: 2},
+		{Word: "abandon", Difficulty: 2},
+		{Word: "desert", Difficulty: 3},
+		{Word: "forsake", Difficulty: 4},
+		{Word: "relinquish", Difficulty: 4},
+	}))
+}
+
+func TestDifficultyFilter(t *testing.T) {
+	filter := newTestDifficultyFilter()
+	tests := []struct {
+		name   string
+		target int
+		hints  []Hint
+		want   string
+		notes  int
+	}{
+		{
+			"harder words dropped, easiest first",
+			0,
+			[]Hint{
+				NewHint(HintSynonym, "", "Similar to: forsake"),
+				NewHint(HintSynonym, "", "Similar to: quit"),
+				NewHint(HintSynonym, "", "Similar to: leave"),
+			},
+			"Similar to: leave|Similar to: quit",
+			0,
+		},
+		{
+			"out-of-list words after known ones",
+			0,
+			[]Hint{
+				NewHint(HintRelated, "", "Related to: ditch"),
+				NewHint(HintRelated, "", "Related to: leave"),
+			},
+			"Related to: leave|Related to: ditch",
+			0,
+		},
+		{
+			"other kinds keep their place",
+			0,
+			[]Hint{
+				NewHint(HintDefinition, "", "Definition: to leave behind"),
+				NewHint(HintSynonym, "", "Similar to: desert"),
+				NewHint(HintLength, "", "Length: 7 letters"),
+				NewHint(HintSynonym, "", "Similar to: quit"),
+			},
+			"Definition: to leave behind|Similar to: quit|Length: 7 letters",
+			0,
+		},
+		{
+			"fallback to out-of-list words",
+			0,
+			[]Hint{
+				NewHint(HintSynonym, "", "Similar to: relinquish"),
+				NewHint(HintSynonym, "", "Similar to: ditch"),
+			},
+			"Similar to: ditch",
+			1,
+		},
+		{
+			"fallback to harder words",
+			0,
+			[]Hint{
+				NewHint(HintSynonym, "", "Similar to: relinquish"),
+				NewHint(HintSynonym, "", "Similar to: desert"),
+			},
+			"Similar to: desert|Similar to: relinquish",
+			1,
+		},
+		{
+			"target given",
+			4,
+			[]Hint{
+				NewHint(HintSynonym, "", "Similar to: relinquish"),
+				NewHint(HintSynonym, "", "Similar to: desert"),
+			},
+			"Similar to: desert|Similar to: relinquish",
+			0,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			hints, notes := filter.Filter("abandon", test.target, test.hints)
+			if got := hintTexts(hints); got != test.want {
+				t.Errorf("hints = %s, want %s", got, test.want)
+			}
+			if len(notes) != test.notes {
+				t.Errorf("notes = %q, want %d", notes, test.notes)
+			}
+		})
+	}
+}
+
+func TestDifficultyFilterUnknownTarget(t *testing.T) {
+	hints := []Hint{NewHint(HintSynonym, "", "Similar to: relinquish")}
+	filtered, notes := newTestDifficultyFilter().Filter("ditch", 0, hints)
+	if len(filtered) != 1 || len(notes) != 0 {
+		t.Errorf("words outside the vocabulary should be left alone, got %v, %q", filtered, notes)
+	}
+}
+
+func TestDifficultyFilterNotes(t *testing.T) {
+	hints := []Hint{
+		NewHint(HintSynonym, "", "Similar to: forsake"),
+		NewHint(HintRelated, "", "Related to: ditch"),
+	}
+	_, notes := newTestDifficultyFilter().Filter("quit", 0, hints)
+	joined := strings.Join(notes, "\n")
+	if !strings.Contains(joined, "synonym: no hint words at or below difficulty 2, used harder words") ||
+		!strings.Contains(joined, "related: no hint words at or below difficulty 2, used words outside the vocabulary") {
+		t.Errorf("notes = %q", notes)
+	}
+}
@@ -0,0 +1,367 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	mathrand "math/rand"
+	"sync"
+	"time"
+)
+
+// Game engine errors, checked with errors.Is
+var (
+	ErrGameNotFound = errors.New("game not found")
+	ErrGameOver     = errors.New("game is over")
+	ErrNoMoreHints  = errors.New("no more hints")
+	ErrInvalidGuess = errors.New("invalid guess")
+)
+
+// LetterFeedback tells how one letter of a guess matches the target word
+type LetterFeedback string
+
+// Supported letter feedback, as in Wordle
+const (
+	LetterCorrect LetterFeedback = "correct"
+	LetterPresent LetterFeedback = "present"
+	LetterAbsent  LetterFeedback = "absent"
+)
+
+// GameStatus is the state of a game session
+type GameStatus string
+
+// Supported game statuses
+const (
+	GamePlaying GameStatus = "playing"
+	GameWon     GameStatus = "won"
+	GameLost    GameStatus = "lost"
+)
+
+// GuessResult is the outcome of one guess
+type GuessResult struct {
+	Guess    string           `json:"guess"`
+	Feedback []LetterFeedback `json:"feedback,omitempty"`
+	Correct  bool             `json:"correct"`
+
+	// Valid is false for words the spell checker doesn't know; they don't use up a guess
+	Valid       bool     `json:"valid"`
+	Suggestions []string `json:"suggestions,omitempty"`
+}
+
+// GameOptions chooses the target word and the scoring of a new game
+type GameOptions struct {
+	// Category is a category id, label or name; empty picks from every category
+	Category string `json:"category"`
+
+	// Difficulty is the level 1-3 of the target word; zero picks from every level
+	Difficulty int `json:"difficulty"`
+
+	MaxGuesses     int `json:"maxGuesses"`
+	StartingScore  int `json:"startingScore"`
+	GuessPenalty   int `json:"guessPenalty"`
+	PointsPerLevel int `json:"pointsPerLevel"`
+}
+
+// DefaultGameOptions allows six guesses and charges three points per hint level
+var DefaultGameOptions = GameOptions{
+	MaxGuesses:     6,
+	StartingScore:  100,
+	GuessPenalty:   5,
+	PointsPerLevel: 3,
+}
+
+// GameSession is the state of one game. The answer is only filled in once the game is over.
+type GameSession struct {
+	ID           string        `json:"id"`
+	Category     string        `json:"category,omitempty"`
+	Difficulty   int           `json:"difficulty,omitempty"`
+	Length       int           `json:"length"`
+	Status       GameStatus    `json:"status"`
+	Score        int           `json:"score"`
+	MaxGuesses   int           `json:"maxGuesses"`
+	Guesses      []GuessResult `json:"guesses"`
+	Hints        []Hint        `json:"hints"`
+	HintsLeft    int           `json:"hintsLeft"`
+	NextHintCost int           `json:"nextHintCost"`
+	Answer       string        `json:"answer,omitempty"`
+	CreatedAt    time.Time     `json:"createdAt"`
+	UpdatedAt    time.Time     `json:"updatedAt"`
+
+	word    string
+	ladder  *HintLadder
+	options GameOptions
+}
+
+// snapshot copies the session so it can be returned while the engine keeps playing it
+func (gs *GameSession) snapshot() *GameSession {
+	copied := *gs
+	copied.Guesses = append([]GuessResult{}, gs.Guesses...)
+	copied.Hints = append([]Hint{}, gs.Hints...)
+	copied.HintsLeft = len(gs.ladder.Rungs) - len(gs.Hints)
+	copied.NextHintCost = 0
+	if copied.HintsLeft > 0 {
+		copied.NextHintCost = gs.ladder.Rungs[len(gs.Hints)].Level * gs.options.PointsPerLevel
+	}
+	if gs.Status != GamePlaying {
+		copied.Answer = gs.word
+	}
+	return &copied
+}
+
+// GameEngine runs word-guessing games: it picks a target word, scores guesses
+// with per-letter feedback and unlocks hints from the least revealing up
+type GameEngine struct {
+	hints      *HintGenerator
+	vocabulary *Vocabulary
+	categories Categories
+	spell      *SpellChecker
+
+	// SessionTTL removes games that haven't been played for this long
+	SessionTTL time.Duration
+
+	mu       sync.Mutex
+	sessions map[string]*GameSession
+	random   *mathrand.Rand
+}
+
+// NewGameEngine creates an engine picking words from the vocabulary.
+// Categories, used to find categories by name, and the spell checker,
+// used to reject unknown guesses, may be nil.
+func NewGameEngine(hints *HintGenerator, vocabulary *Vocabulary, categories Categories, spell *SpellChecker) *GameEngine {
+	return &GameEngine{
+		hints:      hints,
+		vocabulary: vocabulary,
+		categories: categories,
+		spell:      spell,
+		SessionTTL: 24 * time.Hour,
+		sessions:   make(map[string]*GameSession),
+		random:     mathrand.New(mathrand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// StartGame picks a target word and prepares its hint ladder.
+// Zero fields of opts are taken from DefaultGameOptions.
+func (ge *GameEngine) StartGame(ctx context.Context, opts GameOptions) (*GameSession, error) {
+	opts = withGameDefaults(opts)
+
+	label := ""
+	if opts.Category != "" {
+		label = opts.Category
+		if ge.categories != nil {
+			category, err := ge.categories.Find(opts.Category)
+			if err != nil {
+				return nil, err
+			}
+			label = category.Label()
+		}
+	}
+
+	candidates := []string{}
+	for _, entry := range SelectWords(ge.vocabulary, label, opts.Difficulty) {
+		if word := gameWord(entry.Word); word != "" {
+			candidates = append(candidates, word)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("no words for category '%s' at difficulty %d", opts.Category, opts.Difficulty)
+	}
+
+	ge.mu.Lock()
+	word := candidates[ge.random.Intn(len(candidates))]
+	ge.mu.Unlock()
+
+	result, err := ge.hints.GenerateHints(ctx, word, HintOptions{Timeout: wordHintTimeout})
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := newGameID()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	session := &GameSession{
+		ID:         id,
+		Category:   label,
+		Difficulty: opts.Difficulty,
+		Length:     len(word),
+		Status:     GamePlaying,
+		Score:      opts.StartingScore,
+		MaxGuesses: opts.MaxGuesses,
+		Guesses:    []GuessResult{},
+		Hints:      []Hint{},
+		CreatedAt:  now,
+		UpdatedAt:  now,
+		word:       word,
+		ladder:     result.Ladder(),
+		options:    opts,
+	}
+
+	ge.mu.Lock()
+	defer ge.mu.Unlock()
+	ge.expireSessions(now)
+	ge.sessions[id] = session
+	return session.snapshot(), nil
+}
+
+// Game returns the current state of a game
+func (ge *GameEngine) Game(id string) (*GameSession, error) {
+	ge.mu.Lock()
+	defer ge.mu.Unlock()
+
+	session, ok := ge.sessions[id]
+	if !ok {
+		return nil, ErrGameNotFound
+	}
+	return session.snapshot(), nil
+}
+
+// Guess scores a guess against the target word. Guesses of the wrong length are
+// rejected with ErrInvalidGuess; words unknown to the spell checker come back
+// with Valid unset and suggestions, without using up a guess.
+func (ge *GameEngine) Guess(id, guess string) (*GuessResult, *GameSession, error) {
+	ge.mu.Lock()
+	defer ge.mu.Unlock()
+
+	session, ok := ge.sessions[id]
+	if !ok {
+		return nil, nil, ErrGameNotFound
+	}
+	if session.Status != GamePlaying {
+		return nil, session.snapshot(), ErrGameOver
+	}
+
+	guess = normalizeWord(guess)
+	if len(guess) != len(session.word) || gameWord(guess) == "" {
+		return nil, session.snapshot(), fmt.Errorf("%w: want a word of %d letters", ErrInvalidGuess, len(session.word))
+	}
+
+	result := &GuessResult{Guess: guess, Valid: true}
+	if guess != session.word && ge.spell != nil && !ge.spell.Check(guess) {
+		result.Valid = false
+		result.Suggestions = ge.spell.Suggest(guess, 3)
+		return result, session.snapshot(), nil
+	}
+
+	result.Feedback = letterFeedback(session.word, guess)
+	result.Correct = guess == session.word
+	session.Guesses = append(session.Guesses, *result)
+	session.UpdatedAt = time.Now()
+
+	switch {
+	case result.Correct:
+		session.Status = GameWon
+	case len(session.Guesses) >= session.MaxGuesses:
+		session.Status = GameLost
+		session.Score = 0
+	default:
+		session.Score = max(0, session.Score-session.options.GuessPenalty)
+	}
+
+	return result, session.snapshot(), nil
+}
+
+// UnlockHint reveals the next hint of the ladder and charges its cost
+func (ge *GameEngine) UnlockHint(id string) (*Hint, *GameSession, error) {
+	ge.mu.Lock()
+	defer ge.mu.Unlock()
+
+	session, ok := ge.sessions[id]
+	if !ok {
+		return nil, nil, ErrGameNotFound
+	}
+	if session.Status != GamePlaying {
+		return nil, session.snapshot(), ErrGameOver
+	}
+	if len(session.Hints) >= len(session.ladder.Rungs) {
+		return nil, session.snapshot(), ErrNoMoreHints
+	}
+
+	hint := session.ladder.Rungs[len(session.Hints)]
+	session.Hints = append(session.Hints, hint)
+	session.Score = max(0, session.Score-hint.Level*session.options.PointsPerLevel)
+	session.UpdatedAt = time.Now()
+
+	return &hint, session.snapshot(), nil
+}
+
+// expireSessions drops games idle for longer than SessionTTL; the caller holds ge.mu
+func (ge *GameEngine) expireSessions(now time.Time) {
+	if ge.SessionTTL <= 0 {
+		return
+	}
+	for id, session := range ge.sessions {
+		if now.Sub(session.UpdatedAt) > ge.SessionTTL {
+			delete(ge.sessions, id)
+		}
+	}
+}
+
+// letterFeedback marks each letter of the guess as in Wordle: letters in the right
+// place first, then letters elsewhere in the word, each target letter used once
+func letterFeedback(word, guess string) []LetterFeedback {
+	feedback := make([]LetterFeedback, len(guess))
+	remaining := map[byte]int{}
+	for i := 0; i < len(word); i++ {
+		if guess[i] == word[i] {
+			feedback[i] = LetterCorrect
+		} else {
+			remaining[word[i]]++
+		}
+	}
+	for i := 0; i < len(guess); i++ {
+		if feedback[i] == LetterCorrect {
+			continue
+		}
+		if remaining[guess[i]] > 0 {
+			feedback[i] = LetterPresent
+			remaining[guess[i]]--
+		} else {
+			feedback[i] = LetterAbsent
+		}
+	}
+	return feedback
+}
+
+// gameWord lowercases a word, or returns "" when it is not a single word of 4 to 10 letters
+func gameWord(word string) string {
+	word = normalizeWord(word)
+	if len(word) < 4 || len(word) > 10 {
+		return ""
+	}
+	for _, letter := range word {
+		if letter < 'a' || letter > 'z' {
+			return ""
+		}
+	}
+	return word
+}
+
+// withGameDefaults fills the zero fields of opts from DefaultGameOptions
+func withGameDefaults(opts GameOptions) GameOptions {
+	if opts.MaxGuesses <= 0 {
+		opts.MaxGuesses = DefaultGameOptions.MaxGuesses
+	}
+	if opts.StartingScore <= 0 {
+		opts.StartingScore = DefaultGameOptions.StartingScore
+	}
+	if opts.GuessPenalty <= 0 {
+		opts.GuessPenalty = DefaultGameOptions.GuessPenalty
+	}
+	if opts.PointsPerLevel <= 0 {
+		opts.PointsPerLevel = DefaultGameOptions.PointsPerLevel
+	}
+	return opts
+}
+
+// newGameID returns a random session id
+func newGameID() (string, error) {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return "", fmt.Errorf("error creating game id: %v", err)
+	}
+	return hex.EncodeToString(id), nil
+}
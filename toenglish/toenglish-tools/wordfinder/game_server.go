@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// GuessRequest is the body of POST /games/{id}/guesses
+type GuessRequest struct {
+	Guess string `json:"guess"`
+}
+
+// GuessResponse returns the scored guess with the updated game
+type GuessResponse struct {
+	Result *GuessResult `json:"result"`
+	Game   *GameSession `json:"game"`
+}
+
+// HintUnlockResponse returns the unlocked hint with the updated game
+type HintUnlockResponse struct {
+	Hint *Hint        `json:"hint"`
+	Game *GameSession `json:"game"`
+}
+
+// GameServer exposes a GameEngine over HTTP
+type GameServer struct {
+	engine *GameEngine
+
+	// Timeout bounds picking a word and generating its hints
+	Timeout time.Duration
+}
+
+// NewGameServer creates a server for the engine's games
+func NewGameServer(engine *GameEngine) *GameServer {
+	return &GameServer{engine: engine, Timeout: 10 * time.Second}
+}
+
+// Register adds the game routes to a mux
+func (gs *GameServer) Register(mux *http.ServeMux) {
+	mux.HandleFunc("POST /games", gs.handleStart)
+	mux.HandleFunc("GET /games/{id}", gs.handleGame)
+	mux.HandleFunc("POST /games/{id}/guesses", gs.handleGuess)
+	mux.HandleFunc("POST /games/{id}/hints", gs.handleHint)
+}
+
+// handleStart serves POST /games with GameOptions as the body; an empty body uses the defaults
+func (gs *GameServer) handleStart(w http.ResponseWriter, r *http.Request) {
+	var opts GameOptions
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<16))
+	if err := decoder.Decode(&opts); err != nil && !errors.Is(err, io.EOF) {
+		writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+		return
+	}
+	if opts.Difficulty < 0 || opts.Difficulty > 3 {
+		writeJSONError(w, http.StatusBadRequest, "difficulty must be between 0 (any) and 3")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), gs.Timeout)
+	defer cancel()
+
+	session, err := gs.engine.StartGame(ctx, opts)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusCreated, session)
+}
+
+// handleGame serves GET /games/{id}
+func (gs *GameServer) handleGame(w http.ResponseWriter, r *http.Request) {
+	session, err := gs.engine.Game(r.PathValue("id"))
+	if err != nil {
+		writeGameError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, session)
+}
+
+// handleGuess serves POST /games/{id}/guesses with a GuessRequest body
+func (gs *GameServer) handleGuess(w http.ResponseWriter, r *http.Request) {
+	var request GuessRequest
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<16))
+	if err := decoder.Decode(&request); err != nil {
+		writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+		return
+	}
+
+	result, session, err := gs.engine.Guess(r.PathValue("id"), request.Guess)
+	if err != nil {
+		writeGameError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, GuessResponse{Result: result, Game: session})
+}
+
+// handleHint serves POST /games/{id}/hints, unlocking the next hint
+func (gs *GameServer) handleHint(w http.ResponseWriter, r *http.Request) {
+	hint, session, err := gs.engine.UnlockHint(r.PathValue("id"))
+	if err != nil {
+		writeGameError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, HintUnlockResponse{Hint: hint, Game: session})
+}
+
+// writeGameError maps game engine errors to HTTP statuses
+func writeGameError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrGameNotFound):
+		writeJSONError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrGameOver), errors.Is(err, ErrNoMoreHints):
+		writeJSONError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, ErrInvalidGuess):
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+	default:
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+	}
+}
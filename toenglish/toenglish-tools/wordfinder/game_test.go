@@ -0,0 +1,69 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// feedbackString writes feedback as G (correct), Y (present) and . (absent)
+func feedbackString(feedback []LetterFeedback) string {
+	var text strings.Builder
+	for _, letter := range feedback {
+		switch letter {
+		case LetterCorrect:
+			text.WriteByte('G')
+		case LetterPresent:
+			text.WriteByte('Y')
+		default:
+			text.WriteByte('.')
+		}
+	}
+	return text.String()
+}
+
+func TestLetterFeedback(t *testing.T) {
+	tests := []struct {
+		word  string
+		guess string
+		want  string
+	}{
+		{"crane", "crane", "GGGGG"},
+		{"crane", "nacre", "YYYYG"},
+		{"crane", "pious", "....."},
+
+		// A repeated guess letter is only marked as often as the word has it
+		{"crane", "eerie", "..Y.G"},
+		{"abbey", "babes", "YYGG."},
+		{"abbey", "kebab", ".YGYY"},
+
+		// An exact match takes the letter before an earlier misplaced copy
+		{"apple", "ppxxp", "YG..."},
+		{"apple", "pppll", ".GGG."},
+		{"level", "eeeee", ".G.G."},
+	}
+	for _, test := range tests {
+		if got := feedbackString(letterFeedback(test.word, test.guess)); got != test.want {
+			t.Errorf("letterFeedback(%q, %q) = %s, want %s", test.word, test.guess, got, test.want)
+		}
+	}
+}
+
+func TestGameWord(t *testing.T) {
+	tests := []struct {
+		word string
+		want string
+	}{
+		{"Crane", "crane"},
+		{" abandon ", "abandon"},
+		{"cat", ""},
+		{"extraordinary", ""},
+		{"give up", ""},
+		{"don't", ""},
+		{"café", ""},
+	}
+	for _, test := range tests {
+		if got := gameWord(test.word); got != test.want {
+			t.Errorf("gameWord(%q) = %q, want %q", test.word, got, test.want)
+		}
+	}
+}
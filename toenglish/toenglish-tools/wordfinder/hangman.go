@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"unicode"
+)
+
+// hangmanPatternKinds are the hint kinds that show some of the letters of a round
+var hangmanPatternKinds = map[HintKind]bool{
+	HintMaskedPattern:    true,
+	HintVowelPattern:     true,
+	HintConsonantPattern: true,
+}
+
+// HangmanRound is one word to guess letter by letter
+type HangmanRound struct {
+	Number int    `json:"number"`
+	Answer string `json:"answer"`
+
+	// Pattern is the word as first shown, from its masked-pattern hint
+	Pattern string `json:"pattern"`
+
+	// Clue is a definition or synonym, when one is available
+	Clue     string   `json:"clue,omitempty"`
+	ClueKind HintKind `json:"clueKind,omitempty"`
+
+	// Hints are the other letter patterns, to unlock when the learner is stuck
+	Hints    []Hint `json:"hints,omitempty"`
+	MaxWrong int    `json:"maxWrong"`
+}
+
+// HangmanSet is a series of hangman rounds for one category or level
+type HangmanSet struct {
+	Title      string         `json:"title"`
+	Category   string         `json:"category,omitempty"`
+	Difficulty int            `json:"difficulty,omitempty"`
+	Seed       int64          `json:"seed"`
+	Rounds     []HangmanRound `json:"rounds"`
+}
+
+// HangmanOptions controls the hangman generator
+type HangmanOptions struct {
+	// Rounds is the number of words to pick
+	Rounds int
+
+	// MaxWrong is the number of wrong letters allowed per round
+	MaxWrong int
+
+	// Seed makes the word choice reproducible
+	Seed int64
+}
+
+// DefaultHangmanOptions picks 10 words allowing 6 wrong letters each
+var DefaultHangmanOptions = HangmanOptions{
+	Rounds:   10,
+	MaxWrong: 6,
+}
+
+// HangmanGenerator builds hangman rounds from vocabulary words and their pattern hints
+type HangmanGenerator struct {
+	hints *HintGenerator
+}
+
+// NewHangmanGenerator creates a generator that takes its patterns and clues from the hint generator
+func NewHangmanGenerator(hints *HintGenerator) *HangmanGenerator {
+	return &HangmanGenerator{hints: hints}
+}
+
+// Generate picks words in a seeded random order. Each round needs a
+// masked-pattern hint to start from; words without one are skipped.
+func (hg *HangmanGenerator) Generate(ctx context.Context, entries []VocabularyEntry, opts HangmanOptions) (*HangmanSet, error) {
+	if opts.Rounds <= 0 || opts.MaxWrong <= 0 {
+		return nil, fmt.Errorf("invalid hangman options: %d rounds with %d wrong letters", opts.Rounds, opts.MaxWrong)
+	}
+	random := rand.New(rand.NewSource(opts.Seed))
+
+	answers := []string{}
+	seen := map[string]bool{}
+	for _, entry := range entries {
+		answer := hangmanAnswer(entry.Word)
+		if answer != "" && !seen[answer] {
+			seen[answer] = true
+			answers = append(answers, answer)
+		}
+	}
+	random.Shuffle(len(answers), func(i, j int) {
+		answers[i], answers[j] = answers[j], answers[i]
+	})
+
+	set := &HangmanSet{Seed: opts.Seed, Rounds: []HangmanRound{}}
+	err := forEachWordHints(ctx, hg.hints, answers, func(answer string, result *HintResult) bool {
+		if round, ok := hangmanRound(answer, result); ok {
+			round.Number = len(set.Rounds) + 1
+			round.MaxWrong = opts.MaxWrong
+			set.Rounds = append(set.Rounds, round)
+		}
+		return len(set.Rounds) < opts.Rounds
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(set.Rounds) == 0 {
+		return nil, fmt.Errorf("none of %d words has a masked pattern", len(answers))
+	}
+	return set, nil
+}
+
+// hangmanRound builds the round of one word from its hint ladder
+func hangmanRound(answer string, result *HintResult) (HangmanRound, bool) {
+	if result == nil {
+		return HangmanRound{}, false
+	}
+
+	round := HangmanRound{Answer: answer}
+	for _, hint := range result.Ladder().Rungs {
+		switch {
+		case hint.Kind == HintMaskedPattern && round.Pattern == "":
+			round.Pattern = hintContent(hint.Text)
+		case hangmanPatternKinds[hint.Kind]:
+			round.Hints = append(round.Hints, hint)
+		}
+	}
+	if round.Pattern == "" {
+		return HangmanRound{}, false
+	}
+
+	for _, kind := range crosswordClueKinds {
+		for _, hint := range result.Hints {
+			if hint.Kind == kind && round.Clue == "" {
+				round.Clue = hintContent(hint.Text)
+				round.ClueKind = kind
+			}
+		}
+	}
+	return round, true
+}
+
+// hangmanAnswer lowercases a word or phrase, or returns "" when it has fewer
+// than three letters or characters other than letters, spaces and hyphens
+func hangmanAnswer(word string) string {
+	answer := normalizeWord(word)
+	letters := 0
+	for _, r := range answer {
+		switch {
+		case unicode.IsLetter(r):
+			letters++
+		case r == ' ' || r == '-':
+		default:
+			return ""
+		}
+	}
+	if letters < 3 {
+		return ""
+	}
+	return answer
+}
@@ -0,0 +1,107 @@
+package main
+
+import "sort"
+
+// HintKind identifies what a hint tells the learner about the word
+type HintKind string
+
+// Supported hint kinds
+const (
+	HintDefinition       HintKind = "definition"
+	HintSynonym          HintKind = "synonym"
+	HintSoundsLike       HintKind = "sounds-like"
+	HintRelated          HintKind = "related"
+	HintLength           HintKind = "length"
+	HintFirstLetter      HintKind = "first-letter"
+	HintLastLetter       HintKind = "last-letter"
+	HintMaskedPattern    HintKind = "masked-pattern"
+	HintSyllables        HintKind = "syllables"
+	HintVowelPattern     HintKind = "vowel-pattern"
+	HintConsonantPattern HintKind = "consonant-pattern"
+	HintAnagram          HintKind = "anagram"
+	HintCloze            HintKind = "cloze"
+	HintRhyme            HintKind = "rhyme"
+)
+
+// revealLevels ranks each hint kind by how much it gives away, 1 being the least
+var revealLevels = map[HintKind]int{
+	HintRelated:          1,
+	HintDefinition:       2,
+	HintSoundsLike:       3,
+	HintSynonym:          3,
+	HintCloze:            3,
+	HintRhyme:            3,
+	HintLength:           4,
+	HintSyllables:        4,
+	HintFirstLetter:      5,
+	HintLastLetter:       5,
+	HintMaskedPattern:    6,
+	HintVowelPattern:     6,
+	HintConsonantPattern: 7,
+	HintAnagram:          7,
+}
+
+// MaxRevealLevel is the reveal level of the most revealing hint kind
+const MaxRevealLevel = 7
+
+// Hint is a single clue about a word
+type Hint struct {
+	Kind   HintKind `bson:"kind" json:"kind"`
+	Text   string   `bson:"text" json:"text"`
+	Source string   `bson:"source" json:"source"`
+	Level  int      `bson:"level" json:"level"`
+}
+
+// NewHint creates a hint with the reveal level of its kind
+func NewHint(kind HintKind, source, text string) Hint {
+	return Hint{
+		Kind:   kind,
+		Text:   text,
+		Source: source,
+		Level:  revealLevels[kind],
+	}
+}
+
+// HintLadder orders hints from least to most revealing so they can be unlocked one by one
+type HintLadder struct {
+	Word  string `json:"word"`
+	Rungs []Hint `json:"rungs"`
+}
+
+// NewHintLadder sorts the hints by reveal level, keeping the source priority within a level
+func NewHintLadder(word string, hints []Hint) *HintLadder {
+	rungs := make([]Hint, len(hints))
+	copy(rungs, hints)
+	sort.SliceStable(rungs, func(i, j int) bool {
+		return rungs[i].Level < rungs[j].Level
+	})
+	return &HintLadder{Word: word, Rungs: rungs}
+}
+
+// Unlocked returns the first n rungs of the ladder
+func (hl *HintLadder) Unlocked(n int) []Hint {
+	if n > len(hl.Rungs) {
+		n = len(hl.Rungs)
+	}
+	if n < 0 {
+		n = 0
+	}
+	return hl.Rungs[:n]
+}
+
+// UpToLevel returns every rung whose reveal level is at most level
+func (hl *HintLadder) UpToLevel(level int) []Hint {
+	n := sort.Search(len(hl.Rungs), func(i int) bool {
+		return hl.Rungs[i].Level > level
+	})
+	return hl.Rungs[:n]
+}
+
+// Cost returns the points charged for unlocking the first n rungs
+func (hl *HintLadder) Cost(n, pointsPerLevel int) int {
+	total := 0
+	for _, hint := range hl.Unlocked(n) {
+		total += hint.Level * pointsPerLevel
+	}
+	return total
+}
@@ -0,0 +1,289 @@
+package main
+
+import (
+	"container/list"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DataMuse relations that are cached, named after their query parameters
+const (
+	RelationDefinition = "sp+md=d"
+	RelationSynonym    = "rel_syn"
+	RelationSoundsLike = "sl"
+	RelationRelated    = "rel_trg"
+)
+
+// CacheStats reports how a cache has been used since it was created
+type CacheStats struct {
+	Hits      int64 `json:"hits"`
+	Misses    int64 `json:"misses"`
+	Expired   int64 `json:"expired"`
+	Evictions int64 `json:"evictions"`
+	Entries   int   `json:"entries"`
+}
+
+// HintCache interface defines the methods any DataMuse response cache must implement
+type HintCache interface {
+	// Get returns the cached response for the relation and word
+	Get(relation, word string) ([]DataMuseWord, bool)
+
+	// Set stores the response for the relation and word
+	Set(relation, word string, words []DataMuseWord) error
+
+	// Stats returns the usage statistics of the cache
+	Stats() CacheStats
+}
+
+// cacheKey builds the key used to store a relation/word pair
+func cacheKey(relation, word string) string {
+	return relation + "|" + word
+}
+
+// lruEntry is a single element of the in-memory LRU list
+type lruEntry struct {
+	key      string
+	words    []DataMuseWord
+	storedAt time.Time
+}
+
+// LRUCache is an in-memory least-recently-used cache with an optional TTL
+type LRUCache struct {
+	mu       sync.Mutex
+	capacity int
+	ttl      time.Duration
+	order    *list.List
+	items    map[string]*list.Element
+	stats    CacheStats
+}
+
+// NewLRUCache creates an in-memory cache holding at most capacity entries.
+// A ttl of zero keeps entries until they are evicted.
+func NewLRUCache(capacity int, ttl time.Duration) *LRUCache {
+	return &LRUCache{
+		capacity: capacity,
+		ttl:      ttl,
+		order:    list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+// Get returns the cached response for the relation and word
+func (lc *LRUCache) Get(relation, word string) ([]DataMuseWord, bool) {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	elem, ok := lc.items[cacheKey(relation, word)]
+	if !ok {
+		lc.stats.Misses++
+		return nil, false
+	}
+
+	entry := elem.Value.(*lruEntry)
+	if lc.ttl > 0 && time.Since(entry.storedAt) > lc.ttl {
+		lc.order.Remove(elem)
+		delete(lc.items, entry.key)
+		lc.stats.Expired++
+		lc.stats.Misses++
+		return nil, false
+	}
+
+	lc.order.MoveToFront(elem)
+	lc.stats.Hits++
+	return entry.words, true
+}
+
+// Set stores the response for the relation and word
+func (lc *LRUCache) Set(relation, word string, words []DataMuseWord) error {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	key := cacheKey(relation, word)
+	if elem, ok := lc.items[key]; ok {
+		entry := elem.Value.(*lruEntry)
+		entry.words = words
+		entry.storedAt = time.Now()
+		lc.order.MoveToFront(elem)
+		return nil
+	}
+
+	lc.items[key] = lc.order.PushFront(&lruEntry{key: key, words: words, storedAt: time.Now()})
+
+	// Evict the least recently used entries
+	for lc.capacity > 0 && lc.order.Len() > lc.capacity {
+		oldest := lc.order.Back()
+		lc.order.Remove(oldest)
+		delete(lc.items, oldest.Value.(*lruEntry).key)
+		lc.stats.Evictions++
+	}
+
+	return nil
+}
+
+// Stats returns the usage statistics of the cache
+func (lc *LRUCache) Stats() CacheStats {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	stats := lc.stats
+	stats.Entries = lc.order.Len()
+	return stats
+}
+
+// diskEntry is the JSON document stored for every cached response
+type diskEntry struct {
+	Relation string         `json:"relation"`
+	Word     string         `json:"word"`
+	StoredAt time.Time      `json:"storedAt"`
+	Words    []DataMuseWord `json:"words"`
+}
+
+// DiskCache stores every response as a JSON file under a directory
+type DiskCache struct {
+	mu       sync.Mutex
+	dir      string
+	capacity int
+	ttl      time.Duration
+	stats    CacheStats
+}
+
+// NewDiskCache opens (and creates if needed) an on-disk cache directory
+// holding at most capacity entries, or any number for a capacity of zero.
+// A ttl of zero keeps entries until they are evicted.
+func NewDiskCache(dir string, capacity int, ttl time.Duration) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %v", err)
+	}
+
+	entries, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cache directory: %v", err)
+	}
+
+	return &DiskCache{
+		dir:      dir,
+		capacity: capacity,
+		ttl:      ttl,
+		stats:    CacheStats{Entries: len(entries)},
+	}, nil
+}
+
+// path returns the file that holds the relation/word pair
+func (dc *DiskCache) path(relation, word string) string {
+	sum := sha1.Sum([]byte(cacheKey(relation, word)))
+	return filepath.Join(dc.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// Get returns the cached response for the relation and word, deleting it once it has expired
+func (dc *DiskCache) Get(relation, word string) ([]DataMuseWord, bool) {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+
+	path := dc.path(relation, word)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		dc.stats.Misses++
+		return nil, false
+	}
+
+	var entry diskEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		dc.stats.Misses++
+		return nil, false
+	}
+
+	if dc.ttl > 0 && time.Since(entry.StoredAt) > dc.ttl {
+		if os.Remove(path) == nil {
+			dc.stats.Entries--
+		}
+		dc.stats.Expired++
+		dc.stats.Misses++
+		return nil, false
+	}
+
+	dc.stats.Hits++
+	return entry.Words, true
+}
+
+// Set stores the response for the relation and word
+func (dc *DiskCache) Set(relation, word string, words []DataMuseWord) error {
+	data, err := json.Marshal(diskEntry{
+		Relation: relation,
+		Word:     word,
+		StoredAt: time.Now(),
+		Words:    words,
+	})
+	if err != nil {
+		return fmt.Errorf("error marshaling cache entry: %v", err)
+	}
+
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+
+	path := dc.path(relation, word)
+	_, statErr := os.Stat(path)
+
+	// Write to a temporary file first so readers never see a partial entry
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("error writing cache entry: %v", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("error saving cache entry: %v", err)
+	}
+
+	if os.IsNotExist(statErr) {
+		dc.stats.Entries++
+	}
+	if dc.capacity > 0 && dc.stats.Entries > dc.capacity {
+		dc.evict()
+	}
+	return nil
+}
+
+// evict deletes the least recently stored entries. It trims the cache a tenth
+// below its capacity so that the directory isn't listed again on every Set.
+func (dc *DiskCache) evict() {
+	paths, err := filepath.Glob(filepath.Join(dc.dir, "*.json"))
+	if err != nil {
+		return
+	}
+
+	type storedFile struct {
+		path    string
+		modTime time.Time
+	}
+	files := make([]storedFile, 0, len(paths))
+	for _, path := range paths {
+		if info, err := os.Stat(path); err == nil {
+			files = append(files, storedFile{path: path, modTime: info.ModTime()})
+		}
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.Before(files[j].modTime)
+	})
+
+	keep := dc.capacity - dc.capacity/10
+	removed := 0
+	for _, file := range files[:max(len(files)-keep, 0)] {
+		if os.Remove(file.path) == nil {
+			removed++
+		}
+	}
+	dc.stats.Entries = len(files) - removed
+	dc.stats.Evictions += int64(removed)
+}
+
+// Stats returns the usage statistics of the cache
+func (dc *DiskCache) Stats() CacheStats {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+	return dc.stats
+}
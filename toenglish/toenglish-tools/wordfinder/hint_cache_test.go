@@ -0,0 +1,157 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLRUCacheEviction(t *testing.T) {
+	cache := NewLRUCache(2, 0)
+	cache.Set(RelationSynonym, "a", []DataMuseWord{{Word: "one"}})
+	cache.Set(RelationSynonym, "b", []DataMuseWord{{Word: "two"}})
+
+	// Reading "a" makes "b" the least recently used entry
+	if _, ok := cache.Get(RelationSynonym, "a"); !ok {
+		t.Fatal("a is missing")
+	}
+	cache.Set(RelationSynonym, "c", []DataMuseWord{{Word: "three"}})
+
+	tests := []struct {
+		relation string
+		word     string
+		want     bool
+	}{
+		{RelationSynonym, "a", true},
+		{RelationSynonym, "b", false},
+		{RelationSynonym, "c", true},
+		{RelationRelated, "a", false},
+	}
+	for _, test := range tests {
+		if _, ok := cache.Get(test.relation, test.word); ok != test.want {
+			t.Errorf("Get(%s, %s) found %v, want %v", test.relation, test.word, ok, test.want)
+		}
+	}
+
+	stats := cache.Stats()
+	if stats.Evictions != 1 || stats.Entries != 2 || stats.Hits != 3 || stats.Misses != 2 {
+		t.Errorf("stats = %+v", stats)
+	}
+}
+
+func TestLRUCacheTTL(t *testing.T) {
+	cache := NewLRUCache(10, time.Minute)
+	cache.Set(RelationDefinition, "old", nil)
+	cache.Set(RelationDefinition, "new", nil)
+	cache.items[cacheKey(RelationDefinition, "old")].Value.(*lruEntry).storedAt = time.Now().Add(-time.Hour)
+
+	if _, ok := cache.Get(RelationDefinition, "old"); ok {
+		t.Error("an expired entry was returned")
+	}
+	if _, ok := cache.Get(RelationDefinition, "new"); !ok {
+		t.Error("a fresh entry is missing")
+	}
+	if stats := cache.Stats(); stats.Expired != 1 || stats.Entries != 1 {
+		t.Errorf("stats = %+v", stats)
+	}
+}
+
+// ageDiskEntry stores an entry as if it had been stored age ago
+func ageDiskEntry(t *testing.T, cache *DiskCache, relation, word string, age time.Duration) {
+	t.Helper()
+	if err := cache.Set(relation, word, nil); err != nil {
+		t.Fatal(err)
+	}
+	stored := time.Now().Add(-age)
+	data, err := json.Marshal(diskEntry{Relation: relation, Word: word, StoredAt: stored, Words: []DataMuseWord{{Word: word}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := cache.path(relation, word)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, stored, stored); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDiskCacheTTL(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := NewDiskCache(dir, 0, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ageDiskEntry(t, cache, RelationSynonym, "old", time.Hour)
+	ageDiskEntry(t, cache, RelationSynonym, "new", time.Second)
+
+	tests := []struct {
+		word string
+		want bool
+	}{
+		{"old", false},
+		{"new", true},
+		{"missing", false},
+	}
+	for _, test := range tests {
+		words, ok := cache.Get(RelationSynonym, test.word)
+		if ok != test.want || (ok && words[0].Word != test.word) {
+			t.Errorf("Get(%s) = %v, %v", test.word, words, ok)
+		}
+	}
+
+	// Expired entries are deleted, and a reopened cache counts what is left
+	if _, err := os.Stat(cache.path(RelationSynonym, "old")); !os.IsNotExist(err) {
+		t.Error("the expired entry is still on disk")
+	}
+	if stats := cache.Stats(); stats.Expired != 1 || stats.Entries != 1 || stats.Hits != 1 || stats.Misses != 2 {
+		t.Errorf("stats = %+v", stats)
+	}
+	reopened, err := NewDiskCache(dir, 0, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats := reopened.Stats(); stats.Entries != 1 {
+		t.Errorf("reopened with %d entries, want 1", stats.Entries)
+	}
+}
+
+func TestDiskCacheCapacity(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := NewDiskCache(dir, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	words := []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"}
+	for i, word := range words {
+		ageDiskEntry(t, cache, RelationRelated, word, time.Duration(len(words)-i)*time.Minute)
+	}
+	if err := cache.Set(RelationRelated, "k", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Going over the capacity trims the oldest entries to a tenth below it
+	stats := cache.Stats()
+	if stats.Entries != 9 || stats.Evictions != 2 {
+		t.Errorf("stats = %+v, want 9 entries and 2 evictions", stats)
+	}
+	tests := []struct {
+		word string
+		want bool
+	}{
+		{"a", false},
+		{"b", false},
+		{"c", true},
+		{"k", true},
+	}
+	for _, test := range tests {
+		if _, ok := cache.Get(RelationRelated, test.word); ok != test.want {
+			t.Errorf("Get(%s) found %v, want %v", test.word, ok, test.want)
+		}
+	}
+	if files, _ := filepath.Glob(filepath.Join(dir, "*.json")); len(files) != 9 {
+		t.Errorf("%d files on disk, want 9", len(files))
+	}
+}
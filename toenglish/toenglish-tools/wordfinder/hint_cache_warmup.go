@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// diskCacheCapacity bounds the on-disk cache, well above every relation of the word lists
+const diskCacheCapacity = 50000
+
+// newHintCache creates the cache selected on the command line
+func newHintCache(kind, dir string, ttl time.Duration) (HintCache, error) {
+	switch kind {
+	case "none", "":
+		return nil, nil
+	case "memory":
+		return NewLRUCache(20000, ttl), nil
+	case "disk":
+		cache, err := NewDiskCache(dir, diskCacheCapacity, ttl)
+		if err != nil {
+			return nil, err
+		}
+		return cache, nil
+	default:
+		return nil, fmt.Errorf("unknown cache type '%s' (want none, memory or disk)", kind)
+	}
+}
+
+// readWordList reads one word per line, skipping blank lines
+func readWordList(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening word list: %v", err)
+	}
+	defer file.Close()
+
+	words := []string{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		word := strings.ToLower(strings.TrimSpace(scanner.Text()))
+		if word != "" {
+			words = append(words, word)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading word list: %v", err)
+	}
+	return words, nil
+}
+
+// runWarmCommand pre-fetches every DataMuse relation for a word list into the disk cache
+func runWarmCommand(args []string) error {
+	fs := flag.NewFlagSet("warm", flag.ExitOnError)
+	wordsPath := fs.String("words", "../wordcategorizer/oxford3000_clean.txt", "word list with one word per line")
+	cacheDir := fs.String("cache-dir", ".hintcache", "directory of the on-disk hint cache")
+	ttl := fs.Duration("ttl", 30*24*time.Hour, "how long cached responses stay fresh")
+	workers := fs.Int("concurrency", 4, "number of words fetched in parallel")
+	delay := fs.Duration("delay", 100*time.Millisecond, "pause between words per worker")
+	fs.Parse(args)
+
+	words, err := readWordList(*wordsPath)
+	if err != nil {
+		return err
+	}
+
+	cache, err := NewDiskCache(*cacheDir, diskCacheCapacity, *ttl)
+	if err != nil {
+		return err
+	}
+	source := NewDataMuseHintSource(cache)
+
+	fmt.Printf("Warming hint cache in %s for %d words...\n", *cacheDir, len(words))
+
+	var mu sync.Mutex
+	fetchedCount := 0
+	cachedCount := 0
+	errorCount := 0
+
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+	for i := 0; i < *workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for word := range jobs {
+				ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+				fetched, err := source.Prefetch(ctx, word)
+				cancel()
+
+				mu.Lock()
+				switch {
+				case err != nil:
+					fmt.Printf("Error warming '%s': %v\n", word, err)
+					errorCount++
+				case fetched == 0:
+					cachedCount++
+				default:
+					fetchedCount++
+				}
+				mu.Unlock()
+
+				if fetched > 0 {
+					time.Sleep(*delay)
+				}
+			}
+		}()
+	}
+
+	for i, word := range words {
+		if (i+1)%250 == 0 {
+			fmt.Printf("Queued %d/%d words\n", i+1, len(words))
+		}
+		jobs <- word
+	}
+	close(jobs)
+	wg.Wait()
+
+	stats := cache.Stats()
+	fmt.Printf("\n=== Warm-up Summary ===\n")
+	fmt.Printf("Total words: %d\n", len(words))
+	fmt.Printf("Fetched from DataMuse: %d\n", fetchedCount)
+	fmt.Printf("Already cached: %d\n", cachedCount)
+	fmt.Printf("Errors: %d\n", errorCount)
+	fmt.Printf("Cache entries: %d (hits %d, misses %d, expired %d)\n", stats.Entries, stats.Hits, stats.Misses, stats.Expired)
+
+	return nil
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+)
+
+// PrecomputeOptions controls a ProcessAllWordHints run
+type PrecomputeOptions struct {
+	// Hints is passed to GenerateHints for every word
+	Hints HintOptions
+
+	// RefreshOlderThan regenerates saved hints older than this when greater than zero
+	RefreshOlderThan time.Duration
+
+	// AllowPartial saves the hints of words whose sources partly failed.
+	// By default such words are counted as errors and retried on the next run.
+	AllowPartial bool
+
+	// Concurrency is the number of words generated in parallel
+	Concurrency int
+}
+
+// PrecomputeSummary counts the outcome of a ProcessAllWordHints run
+type PrecomputeSummary struct {
+	Total     int
+	Success   int
+	Refreshed int
+	Errors    int
+	Skipped   int
+}
+
+// ProcessAllWordHints generates hints for every vocabulary word and saves them to
+// the vocabularywordhints collection. Words that already have hints are skipped
+// unless they are older than opts.RefreshOlderThan, so an interrupted run
+// resumes where it stopped. Cancelling ctx stops the run after the words in flight.
+func ProcessAllWordHints(ctx context.Context, generator *HintGenerator, mongoClient *MongoDBClient, opts PrecomputeOptions) (*PrecomputeSummary, error) {
+	// Get all vocabulary words
+	fmt.Println("Fetching all vocabulary words from MongoDB...")
+	words, err := mongoClient.GetAllVocabularyWords(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vocabulary words: %v", err)
+	}
+	fmt.Printf("Found %d vocabulary words to process\n", len(words))
+
+	fmt.Println("Checking for existing word hints...")
+	updatedAt, err := mongoClient.GetHintUpdateTimes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get existing word hints: %v", err)
+	}
+	fmt.Printf("Found %d existing word hints\n", len(updatedAt))
+
+	summary := &PrecomputeSummary{Total: len(words)}
+	refreshBefore := time.Time{}
+	if opts.RefreshOlderThan > 0 {
+		refreshBefore = time.Now().Add(-opts.RefreshOlderThan)
+	}
+
+	workers := opts.Concurrency
+	if workers <= 0 {
+		workers = 1
+	}
+
+	// Words already handed to a worker are finished even after ctx is cancelled
+	workCtx := context.WithoutCancel(ctx)
+
+	var mu sync.Mutex
+	jobs := make(chan VocabularyWord)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for word := range jobs {
+				_, existed := updatedAt[word.ID]
+				err := precomputeWordHints(workCtx, generator, mongoClient, word, opts)
+
+				mu.Lock()
+				switch {
+				case err != nil:
+					fmt.Printf("Error processing '%s': %v\n", word.Word, err)
+					summary.Errors++
+				case existed:
+					fmt.Printf("Refreshed hints for '%s'\n", word.Word)
+					summary.Refreshed++
+				default:
+					fmt.Printf("Saved hints for '%s'\n", word.Word)
+					summary.Success++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+queue:
+	for _, word := range words {
+		if saved, ok := updatedAt[word.ID]; ok && (refreshBefore.IsZero() || saved.After(refreshBefore)) {
+			summary.Skipped++
+			continue
+		}
+		select {
+		case jobs <- word:
+		case <-ctx.Done():
+			fmt.Println("Interrupted, finishing the words in progress...")
+			break queue
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	return summary, nil
+}
+
+// precomputeWordHints generates and saves the hints of a single word
+func precomputeWordHints(ctx context.Context, generator *HintGenerator, mongoClient *MongoDBClient, word VocabularyWord, opts PrecomputeOptions) error {
+	result, err := generator.GenerateHints(ctx, word.Word, opts.Hints)
+	if err != nil {
+		return err
+	}
+	if len(result.Errors) > 0 && !opts.AllowPartial {
+		return fmt.Errorf("%d source(s) failed, first: %v", len(result.Errors), result.Errors[0])
+	}
+	if len(result.Hints) == 0 {
+		return fmt.Errorf("no hints generated")
+	}
+
+	sources := []string{}
+	seen := map[string]bool{}
+	for _, hint := range result.Hints {
+		if !seen[hint.Source] {
+			seen[hint.Source] = true
+			sources = append(sources, hint.Source)
+		}
+	}
+
+	return mongoClient.SaveWordHints(ctx, &VocabularyWordHints{
+		VocabularyWordID: word.ID,
+		Word:             result.Word,
+		Hints:            result.Ladder().Rungs,
+		Fallbacks:        result.Fallbacks,
+		Sources:          sources,
+	})
+}
+
+// runPrecomputeCommand generates hints for every vocabulary word ahead of time
+func runPrecomputeCommand(args []string) error {
+	fs := flag.NewFlagSet("precompute", flag.ExitOnError)
+	refresh := fs.Duration("refresh-older-than", 0, "regenerate saved hints older than this, e.g. 720h (0 never refreshes)")
+	maxHints := fs.Int("max", 0, "maximum hints stored per word (0 keeps all)")
+	maxLevel := fs.Int("level", 0, "most revealing hint level stored (0 keeps all)")
+	allowPartial := fs.Bool("allow-partial", false, "save hints even when some sources failed")
+	workers := fs.Int("concurrency", 2, "number of words processed in parallel")
+	timeout := fs.Duration("timeout", 30*time.Second, "hint generation timeout per word")
+	setupFlags := addHintSetupFlags(fs)
+	fs.Parse(args)
+
+	if os.Getenv("MONGODB_URI") == "" {
+		return fmt.Errorf("MONGODB_URI must be set to precompute hints")
+	}
+
+	setup, err := setupFlags.build()
+	if err != nil {
+		return err
+	}
+	defer setup.Close()
+
+	// Stop queueing new words on Ctrl-C; the next run resumes from there
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	summary, err := ProcessAllWordHints(ctx, setup.generator, setup.mongoClient, PrecomputeOptions{
+		Hints: HintOptions{
+			MaxHints: *maxHints,
+			MaxLevel: *maxLevel,
+			Timeout:  *timeout,
+		},
+		RefreshOlderThan: *refresh,
+		AllowPartial:     *allowPartial,
+		Concurrency:      *workers,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\n=== Precompute Summary ===\n")
+	fmt.Printf("Total words: %d\n", summary.Total)
+	fmt.Printf("Successfully processed: %d\n", summary.Success)
+	fmt.Printf("Refreshed: %d\n", summary.Refreshed)
+	fmt.Printf("Errors: %d\n", summary.Errors)
+	fmt.Printf("Skipped (already exists): %d\n", summary.Skipped)
+	if remaining := summary.Total - summary.Success - summary.Refreshed - summary.Errors - summary.Skipped; remaining > 0 {
+		fmt.Printf("Not processed (interrupted): %d\n", remaining)
+	}
+
+	return nil
+}
@@ -0,0 +1,357 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// HintRequest is the body of a batch hint request
+type HintRequest struct {
+	Words   []string `json:"words"`
+	Max     int      `json:"max"`
+	Level   int      `json:"level"`
+	Sources []string `json:"sources"`
+	Leak    string   `json:"leak"`
+}
+
+// BatchHintResponse holds one result per requested word, in request order
+type BatchHintResponse struct {
+	Results []*HintResult `json:"results"`
+}
+
+// ServerMetrics counts requests served by the hint server
+type ServerMetrics struct {
+	Requests      int64      `json:"requests"`
+	Errors        int64      `json:"errors"`
+	WordsServed   int64      `json:"wordsServed"`
+	SourceErrors  int64      `json:"sourceErrors"`
+	AvgLatencyMS  float64    `json:"avgLatencyMs"`
+	UptimeSeconds int64      `json:"uptimeSeconds"`
+	Cache         CacheStats `json:"cache"`
+}
+
+// HintServer exposes the hint generator as a JSON HTTP API
+type HintServer struct {
+	generator *HintGenerator
+	cache     HintCache
+	started   time.Time
+
+	// Timeout bounds the hint generation of each word
+	Timeout time.Duration
+
+	// MaxBatch limits the number of words in one batch request
+	MaxBatch int
+
+	// Concurrency limits how many words of a batch are generated at once
+	Concurrency int
+
+	// CacheMaxAge is sent to clients as the Cache-Control max-age of hint responses
+	CacheMaxAge time.Duration
+
+	requests     atomic.Int64
+	errors       atomic.Int64
+	wordsServed  atomic.Int64
+	sourceErrors atomic.Int64
+	latencyNanos atomic.Int64
+}
+
+// NewHintServer creates a server for the generator; cache may be nil when responses are not cached
+func NewHintServer(generator *HintGenerator, cache HintCache) *HintServer {
+	return &HintServer{
+		generator:   generator,
+		cache:       cache,
+		started:     time.Now(),
+		Timeout:     5 * time.Second,
+		MaxBatch:    100,
+		Concurrency: 8,
+		CacheMaxAge: time.Hour,
+	}
+}
+
+// Handler returns the HTTP handler serving every endpoint
+func (hs *HintServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /hints/{word}", hs.instrument(hs.handleHints))
+	mux.HandleFunc("POST /hints/batch", hs.instrument(hs.handleBatch))
+	mux.HandleFunc("GET /healthz", hs.handleHealth)
+	mux.HandleFunc("GET /metrics", hs.handleMetrics)
+	return mux
+}
+
+// statusRecorder remembers the status code written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader records the status code before writing it
+func (sr *statusRecorder) WriteHeader(status int) {
+	sr.status = status
+	sr.ResponseWriter.WriteHeader(status)
+}
+
+// instrument counts requests, failures and latency of a hint handler
+func (hs *HintServer) instrument(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		handler(recorder, r)
+
+		hs.requests.Add(1)
+		hs.latencyNanos.Add(int64(time.Since(start)))
+		if recorder.status >= http.StatusBadRequest {
+			hs.errors.Add(1)
+		}
+	}
+}
+
+// handleHints serves GET /hints/{word}?max=5&level=3&sources=mongo,offline&leak=stem
+func (hs *HintServer) handleHints(w http.ResponseWriter, r *http.Request) {
+	word := normalizeWord(r.PathValue("word"))
+	if word == "" {
+		writeJSONError(w, http.StatusBadRequest, "missing word")
+		return
+	}
+
+	query := r.URL.Query()
+	request := HintRequest{Leak: query.Get("leak")}
+	var err error
+	if request.Max, err = intParam(query.Get("max")); err != nil {
+		writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("invalid max: %v", err))
+		return
+	}
+	if request.Level, err = intParam(query.Get("level")); err != nil {
+		writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("invalid level: %v", err))
+		return
+	}
+	if sources := query.Get("sources"); sources != "" {
+		request.Sources = strings.Split(sources, ",")
+	}
+
+	opts, err := request.options()
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := hs.generate(r, word, opts)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Only complete results may be cached by clients
+	if len(result.Errors) == 0 && hs.CacheMaxAge > 0 {
+		w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(hs.CacheMaxAge.Seconds())))
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// handleBatch serves POST /hints/batch with a HintRequest body
+func (hs *HintServer) handleBatch(w http.ResponseWriter, r *http.Request) {
+	var request HintRequest
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20))
+	if err := decoder.Decode(&request); err != nil {
+		writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+		return
+	}
+	if len(request.Words) == 0 {
+		writeJSONError(w, http.StatusBadRequest, "no words given")
+		return
+	}
+	if hs.MaxBatch > 0 && len(request.Words) > hs.MaxBatch {
+		writeJSONError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("too many words: %d (max %d)", len(request.Words), hs.MaxBatch))
+		return
+	}
+
+	words := make([]string, len(request.Words))
+	for i, word := range request.Words {
+		if words[i] = normalizeWord(word); words[i] == "" {
+			writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("missing word at index %d", i))
+			return
+		}
+	}
+
+	opts, err := request.options()
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	concurrency := hs.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	response := BatchHintResponse{Results: make([]*HintResult, len(words))}
+	errs := make([]error, len(words))
+	semaphore := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+	for i, word := range words {
+		wg.Add(1)
+		go func(i int, word string) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+			response.Results[i], errs[i] = hs.generate(r, word, opts)
+		}(i, word)
+	}
+	wg.Wait()
+
+	// Option errors are the same for every word, so the first one is enough
+	for _, err := range errs {
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// generate runs the hint generator under the request's context
+func (hs *HintServer) generate(r *http.Request, word string, opts HintOptions) (*HintResult, error) {
+	opts.Timeout = hs.Timeout
+	result, err := hs.generator.GenerateHints(r.Context(), word, opts)
+	if err != nil {
+		return nil, err
+	}
+	hs.wordsServed.Add(1)
+	hs.sourceErrors.Add(int64(len(result.Errors)))
+	return result, nil
+}
+
+// handleHealth serves GET /healthz
+func (hs *HintServer) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"status":  "ok",
+		"sources": hs.generator.SourceNames(),
+	})
+}
+
+// handleMetrics serves GET /metrics
+func (hs *HintServer) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, hs.Metrics())
+}
+
+// Metrics returns a snapshot of the server counters and the cache statistics
+func (hs *HintServer) Metrics() ServerMetrics {
+	metrics := ServerMetrics{
+		Requests:      hs.requests.Load(),
+		Errors:        hs.errors.Load(),
+		WordsServed:   hs.wordsServed.Load(),
+		SourceErrors:  hs.sourceErrors.Load(),
+		UptimeSeconds: int64(time.Since(hs.started).Seconds()),
+	}
+	if metrics.Requests > 0 {
+		metrics.AvgLatencyMS = float64(hs.latencyNanos.Load()) / float64(metrics.Requests) / float64(time.Millisecond)
+	}
+	if hs.cache != nil {
+		metrics.Cache = hs.cache.Stats()
+	}
+	return metrics
+}
+
+// options converts the request parameters into generator options
+func (hr HintRequest) options() (HintOptions, error) {
+	if hr.Max < 0 {
+		return HintOptions{}, fmt.Errorf("max must not be negative")
+	}
+	if hr.Level < 0 || hr.Level > MaxRevealLevel {
+		return HintOptions{}, fmt.Errorf("level must be between 0 (no limit) and %d", MaxRevealLevel)
+	}
+
+	opts := HintOptions{MaxHints: hr.Max, MaxLevel: hr.Level}
+	for _, source := range hr.Sources {
+		if source = strings.TrimSpace(source); source != "" {
+			opts.Sources = append(opts.Sources, source)
+		}
+	}
+	if hr.Leak != "" {
+		strictness, err := ParseLeakStrictness(hr.Leak)
+		if err != nil {
+			return HintOptions{}, err
+		}
+		opts.LeakStrictness = strictness
+	}
+	return opts, nil
+}
+
+// intParam parses an optional integer query parameter
+func intParam(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(value)
+}
+
+// writeJSON writes a value as a JSON response
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		log.Printf("Warning: failed to write response: %v", err)
+	}
+}
+
+// writeJSONError writes an error message as a JSON response
+func writeJSONError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
+
+// runServeCommand starts the HTTP hint service
+func runServeCommand(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", ":8080", "address to listen on")
+	timeout := fs.Duration("timeout", 5*time.Second, "hint generation timeout per word")
+	maxBatch := fs.Int("max-batch", 100, "maximum number of words in a batch request")
+	categoriesPath := fs.String("categories", "../wordcategorizer/cluster_data.json", "category names used to start games")
+	spellModel := fs.String("spell-model", "spell_model.json", "spell model used to validate game guesses")
+	setupFlags := addHintSetupFlags(fs)
+	fs.Parse(args)
+
+	setup, err := setupFlags.build()
+	if err != nil {
+		return err
+	}
+	defer setup.Close()
+
+	hintServer := NewHintServer(setup.generator, setup.cache)
+	hintServer.Timeout = *timeout
+	hintServer.MaxBatch = *maxBatch
+
+	mux := http.NewServeMux()
+	mux.Handle("/", hintServer.Handler())
+
+	// Games need a vocabulary to pick words; categories and spell checking are optional
+	if setup.vocabulary != nil {
+		categories, err := LoadCategories(*categoriesPath)
+		if err != nil {
+			log.Printf("Warning: %v, games can only use category labels", err)
+		}
+		spell, err := LoadSpellChecker(*spellModel)
+		if err != nil {
+			log.Printf("Warning: %v, game guesses won't be spell-checked", err)
+		}
+		NewGameServer(NewGameEngine(setup.generator, setup.vocabulary, categories, spell)).Register(mux)
+	} else {
+		log.Printf("Warning: no vocabulary loaded, games are disabled")
+	}
+
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	log.Printf("Serving hints on %s (sources: %s)", *addr, strings.Join(setup.generator.SourceNames(), ", "))
+	return server.ListenAndServe()
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandleBatch(t *testing.T) {
+	source := &stubSource{name: "stub", hints: []Hint{NewHint(HintRelated, "", "Related to: ship")}}
+	server := httptest.NewServer(NewHintServer(NewHintGenerator(source), nil).Handler())
+	defer server.Close()
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		want   string
+	}{
+		{"words", `{"words":["Abandon","  give   up "]}`, http.StatusOK, "abandon|give up"},
+		{"empty word", `{"words":["abandon",""]}`, http.StatusBadRequest, "missing word at index 1"},
+		{"blank word", `{"words":["   ","abandon"]}`, http.StatusBadRequest, "missing word at index 0"},
+		{"no words", `{"words":[]}`, http.StatusBadRequest, "no words given"},
+		{"bad level", `{"words":["abandon"],"level":9}`, http.StatusBadRequest, "level must be"},
+		{"unknown source", `{"words":["abandon"],"sources":["nope"]}`, http.StatusBadRequest, "unknown hint source"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resp, err := http.Post(server.URL+"/hints/batch", "application/json", strings.NewReader(test.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != test.status {
+				t.Errorf("status = %d, want %d", resp.StatusCode, test.status)
+			}
+
+			if test.status != http.StatusOK {
+				var body map[string]string
+				if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+					t.Fatal(err)
+				}
+				if !strings.Contains(body["error"], test.want) {
+					t.Errorf("error = %q, want %q", body["error"], test.want)
+				}
+				return
+			}
+
+			var response BatchHintResponse
+			if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+				t.Fatal(err)
+			}
+			words := []string{}
+			for _, result := range response.Results {
+				words = append(words, result.Word)
+			}
+			if got := strings.Join(words, "|"); got != test.want {
+				t.Errorf("words = %s, want %s", got, test.want)
+			}
+		})
+	}
+}
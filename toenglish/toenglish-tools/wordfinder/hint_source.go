@@ -0,0 +1,13 @@
+package main
+
+import "context"
+
+// HintSource interface defines the methods any hint backend must implement
+type HintSource interface {
+	// Name returns the unique name used to select the source
+	Name() string
+
+	// Hints returns the hints this source can produce for the word.
+	// A source may return partial hints together with an error.
+	Hints(ctx context.Context, word string) ([]Hint, error)
+}
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// HTTPErrorKind classifies why a request to a remote API failed
+type HTTPErrorKind string
+
+// Supported HTTP error kinds
+const (
+	HTTPErrorNetwork     HTTPErrorKind = "network"
+	HTTPErrorTimeout     HTTPErrorKind = "timeout"
+	HTTPErrorCanceled    HTTPErrorKind = "canceled"
+	HTTPErrorRateLimited HTTPErrorKind = "rate-limited"
+	HTTPErrorServer      HTTPErrorKind = "server"
+	HTTPErrorNotFound    HTTPErrorKind = "not-found"
+	HTTPErrorClient      HTTPErrorKind = "client"
+	HTTPErrorDecode      HTTPErrorKind = "decode"
+)
+
+// HTTPError is returned by HTTPClient for every failed request.
+// Use errors.As to inspect the kind and status code.
+type HTTPError struct {
+	Kind       HTTPErrorKind
+	URL        string
+	StatusCode int
+	Attempts   int
+	Err        error
+
+	// retryAfter is the delay the server asked for, if any
+	retryAfter time.Duration
+}
+
+// Error implements the error interface
+func (he *HTTPError) Error() string {
+	if he.StatusCode != 0 {
+		return fmt.Sprintf("%s error from %s: status %d after %d attempt(s): %v", he.Kind, he.URL, he.StatusCode, he.Attempts, he.Err)
+	}
+	return fmt.Sprintf("%s error from %s after %d attempt(s): %v", he.Kind, he.URL, he.Attempts, he.Err)
+}
+
+// Unwrap returns the underlying error
+func (he *HTTPError) Unwrap() error {
+	return he.Err
+}
+
+// Retryable reports whether the same request may succeed later
+func (he *HTTPError) Retryable() bool {
+	switch he.Kind {
+	case HTTPErrorNetwork, HTTPErrorTimeout, HTTPErrorRateLimited, HTTPErrorServer:
+		return true
+	}
+	return false
+}
+
+// IsHTTPErrorKind reports whether err is an HTTPError of the given kind
+func IsHTTPErrorKind(err error, kind HTTPErrorKind) bool {
+	var httpErr *HTTPError
+	return errors.As(err, &httpErr) && httpErr.Kind == kind
+}
+
+// RateLimiter spaces requests evenly so that at most a fixed number start per second
+type RateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// NewRateLimiter creates a limiter; a nil limiter, returned for perSecond <= 0, never waits
+func NewRateLimiter(perSecond float64) *RateLimiter {
+	if perSecond <= 0 {
+		return nil
+	}
+	return &RateLimiter{interval: time.Duration(float64(time.Second) / perSecond)}
+}
+
+// Wait blocks until the caller may start a request or ctx is done
+func (rl *RateLimiter) Wait(ctx context.Context) error {
+	if rl == nil {
+		return nil
+	}
+
+	rl.mu.Lock()
+	now := time.Now()
+	slot := rl.next
+	if slot.Before(now) {
+		slot = now
+	}
+	rl.next = slot.Add(rl.interval)
+	rl.mu.Unlock()
+
+	return sleepContext(ctx, slot.Sub(now))
+}
+
+// HTTPClient is the shared HTTP layer for remote APIs. It rate limits requests,
+// retries retryable failures with jittered exponential backoff and returns
+// every failure as an *HTTPError.
+type HTTPClient struct {
+	client  *http.Client
+	limiter *RateLimiter
+
+	// MaxRetries is the number of extra attempts after a retryable failure
+	MaxRetries int
+
+	// BaseDelay is the backoff before the first retry; it doubles on each retry up to
+	// MaxDelay, which also caps the delay a server may ask for with Retry-After
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+// NewHTTPClient creates a client with a per-attempt timeout and a request rate limit
+func NewHTTPClient(timeout time.Duration, requestsPerSecond float64) *HTTPClient {
+	return &HTTPClient{
+		client:     &http.Client{Timeout: timeout},
+		limiter:    NewRateLimiter(requestsPerSecond),
+		MaxRetries: 3,
+		BaseDelay:  200 * time.Millisecond,
+		MaxDelay:   5 * time.Second,
+	}
+}
+
+// GetJSON fetches the URL and decodes its JSON body into v
+func (hc *HTTPClient) GetJSON(ctx context.Context, requestURL string, v any) error {
+	var lastErr *HTTPError
+	for attempt := 0; attempt <= hc.MaxRetries; attempt++ {
+		if attempt > 0 {
+			if err := sleepContext(ctx, hc.backoff(attempt, lastErr)); err != nil {
+				return &HTTPError{Kind: HTTPErrorCanceled, URL: requestURL, Attempts: attempt, Err: err}
+			}
+		}
+		if err := hc.limiter.Wait(ctx); err != nil {
+			return &HTTPError{Kind: HTTPErrorCanceled, URL: requestURL, Attempts: attempt, Err: err}
+		}
+
+		lastErr = hc.getJSONOnce(ctx, requestURL, v)
+		if lastErr == nil {
+			return nil
+		}
+		lastErr.Attempts = attempt + 1
+		if !lastErr.Retryable() {
+			return lastErr
+		}
+	}
+	return lastErr
+}
+
+// getJSONOnce makes a single attempt and classifies its failure
+func (hc *HTTPClient) getJSONOnce(ctx context.Context, requestURL string, v any) *HTTPError {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return &HTTPError{Kind: HTTPErrorClient, URL: requestURL, Err: fmt.Errorf("error creating request: %v", err)}
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := hc.client.Do(req)
+	if err != nil {
+		return &HTTPError{Kind: classifyRequestError(ctx, err), URL: requestURL, Err: err}
+	}
+	defer resp.Body.Close()
+
+	if kind, ok := classifyStatus(resp.StatusCode); !ok {
+		httpErr := &HTTPError{Kind: kind, URL: requestURL, StatusCode: resp.StatusCode, Err: errors.New(http.StatusText(resp.StatusCode))}
+		if retryAfter := parseRetryAfter(resp.Header.Get("Retry-After")); retryAfter > 0 {
+			httpErr.Err = fmt.Errorf("%s (retry after %s)", http.StatusText(resp.StatusCode), retryAfter)
+			httpErr.retryAfter = retryAfter
+		}
+		return httpErr
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return &HTTPError{Kind: HTTPErrorDecode, URL: requestURL, StatusCode: resp.StatusCode, Err: err}
+	}
+	return nil
+}
+
+// backoff returns the delay before the given retry. The delay doubles on every
+// retry and is jittered over its upper half. A longer Retry-After from the
+// server wins, but never beyond MaxDelay.
+func (hc *HTTPClient) backoff(attempt int, lastErr *HTTPError) time.Duration {
+	delay := hc.BaseDelay << (attempt - 1)
+	if delay > hc.MaxDelay || delay <= 0 {
+		delay = hc.MaxDelay
+	}
+	delay = delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+	if lastErr != nil && lastErr.retryAfter > delay {
+		delay = min(lastErr.retryAfter, hc.MaxDelay)
+	}
+	return delay
+}
+
+// classifyStatus maps a status code to an error kind; ok is true for 2xx
+func classifyStatus(status int) (HTTPErrorKind, bool) {
+	switch {
+	case status >= 200 && status < 300:
+		return "", true
+	case status == http.StatusTooManyRequests:
+		return HTTPErrorRateLimited, false
+	case status == http.StatusNotFound:
+		return HTTPErrorNotFound, false
+	case status == http.StatusRequestTimeout:
+		return HTTPErrorTimeout, false
+	case status >= 500:
+		return HTTPErrorServer, false
+	default:
+		return HTTPErrorClient, false
+	}
+}
+
+// classifyRequestError tells cancellation and timeouts apart from other network failures
+func classifyRequestError(ctx context.Context, err error) HTTPErrorKind {
+	if ctx.Err() != nil {
+		return HTTPErrorCanceled
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return HTTPErrorTimeout
+	}
+	return HTTPErrorNetwork
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return time.Until(at)
+	}
+	return 0
+}
+
+// sleepContext waits for the duration or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestHTTPClient creates a client that retries quickly
+func newTestHTTPClient() *HTTPClient {
+	client := NewHTTPClient(time.Second, 0)
+	client.BaseDelay = time.Millisecond
+	client.MaxDelay = 4 * time.Millisecond
+	return client
+}
+
+func TestHTTPClientStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		kind     HTTPErrorKind
+		attempts int
+	}{
+		{"ok", http.StatusOK, `{"word":"abandon"}`, "", 1},
+		{"bad json", http.StatusOK, `{"word":`, HTTPErrorDecode, 1},
+
+		// Client errors fail at once, server errors are retried
+		{"not found", http.StatusNotFound, "", HTTPErrorNotFound, 1},
+		{"bad request", http.StatusBadRequest, "", HTTPErrorClient, 1},
+		{"rate limited", http.StatusTooManyRequests, "", HTTPErrorRateLimited, 4},
+		{"request timeout", http.StatusRequestTimeout, "", HTTPErrorTimeout, 4},
+		{"server error", http.StatusBadGateway, "", HTTPErrorServer, 4},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var calls atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls.Add(1)
+				w.WriteHeader(test.status)
+				fmt.Fprint(w, test.body)
+			}))
+			defer server.Close()
+
+			var v struct{ Word string }
+			err := newTestHTTPClient().GetJSON(context.Background(), server.URL, &v)
+			if int(calls.Load()) != test.attempts {
+				t.Errorf("made %d attempts, want %d", calls.Load(), test.attempts)
+			}
+			if test.kind == "" {
+				if err != nil || v.Word != "abandon" {
+					t.Errorf("got %q, %v", v.Word, err)
+				}
+				return
+			}
+
+			var httpErr *HTTPError
+			if !errors.As(err, &httpErr) {
+				t.Fatalf("got %v, want an *HTTPError", err)
+			}
+			if httpErr.Kind != test.kind || httpErr.Attempts != test.attempts || !IsHTTPErrorKind(err, test.kind) {
+				t.Errorf("got %s after %d attempts, want %s after %d", httpErr.Kind, httpErr.Attempts, test.kind, test.attempts)
+			}
+			if test.status != http.StatusOK && httpErr.StatusCode != test.status {
+				t.Errorf("status = %d, want %d", httpErr.StatusCode, test.status)
+			}
+		})
+	}
+}
+
+func TestHTTPClientRetryRecovers(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `{"word":"abandon"}`)
+	}))
+	defer server.Close()
+
+	var v struct{ Word string }
+	if err := newTestHTTPClient().GetJSON(context.Background(), server.URL, &v); err != nil || v.Word != "abandon" {
+		t.Errorf("got %q, %v after %d attempts", v.Word, err, calls.Load())
+	}
+}
+
+func TestHTTPClientNetworkErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	client := newTestHTTPClient()
+	client.MaxRetries = 1
+	var v any
+	if err := client.GetJSON(context.Background(), url, &v); !IsHTTPErrorKind(err, HTTPErrorNetwork) {
+		t.Errorf("closed server: got %v, want a network error", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := client.GetJSON(ctx, url, &v); !IsHTTPErrorKind(err, HTTPErrorCanceled) {
+		t.Errorf("canceled context: got %v, want a canceled error", err)
+	}
+}
+
+func TestHTTPClientBackoff(t *testing.T) {
+	client := &HTTPClient{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	tests := []struct {
+		attempt    int
+		retryAfter time.Duration
+		min, max   time.Duration
+	}{
+		// Doubling, jittered over the upper half
+		{1, 0, 50 * time.Millisecond, 100 * time.Millisecond},
+		{2, 0, 100 * time.Millisecond, 200 * time.Millisecond},
+		{3, 0, 200 * time.Millisecond, 400 * time.Millisecond},
+		{10, 0, 500 * time.Millisecond, time.Second},
+		{70, 0, 500 * time.Millisecond, time.Second},
+
+		// Retry-After wins when longer, up to MaxDelay
+		{1, 300 * time.Millisecond, 300 * time.Millisecond, 300 * time.Millisecond},
+		{1, time.Hour, time.Second, time.Second},
+	}
+	for _, test := range tests {
+		for i := 0; i < 20; i++ {
+			delay := client.backoff(test.attempt, &HTTPError{retryAfter: test.retryAfter})
+			if delay < test.min || delay > test.max {
+				t.Errorf("backoff(%d, retry after %s) = %s, want between %s and %s", test.attempt, test.retryAfter, delay, test.min, test.max)
+				break
+			}
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		value    string
+		min, max time.Duration
+	}{
+		{"", 0, 0},
+		{"5", 5 * time.Second, 5 * time.Second},
+		{"-1", 0, 0},
+		{"soon", 0, 0},
+		{time.Now().Add(time.Minute).UTC().Format(http.TimeFormat), 58 * time.Second, time.Minute},
+	}
+	for _, test := range tests {
+		if got := parseRetryAfter(test.value); got < test.min || got > test.max {
+			t.Errorf("parseRetryAfter(%q) = %s, want between %s and %s", test.value, got, test.min, test.max)
+		}
+	}
+}
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// LeakStrictness controls how aggressively hints that give away the answer are dropped
+type LeakStrictness int
+
+// Supported strictness levels, from most lenient to strictest
+const (
+	// LeakDefault uses the detector's configured strictness
+	LeakDefault LeakStrictness = iota
+	// LeakOff keeps every hint
+	LeakOff
+	// LeakExact drops hints containing the word itself
+	LeakExact
+	// LeakInflection also drops plurals, past tenses, participles and comparatives
+	LeakInflection
+	// LeakStem also drops derivations that share the word's stem or word family
+	LeakStem
+	// LeakStrict also drops words that merely start with or contain the answer
+	LeakStrict
+)
+
+// leakStrictnessNames maps strictness levels to their command-line names
+var leakStrictnessNames = map[string]LeakStrictness{
+	"off":        LeakOff,
+	"exact":      LeakExact,
+	"inflection": LeakInflection,
+	"stem":       LeakStem,
+	"strict":     LeakStrict,
+}
+
+// ParseLeakStrictness converts a strictness name such as "stem" to its level
+func ParseLeakStrictness(name string) (LeakStrictness, error) {
+	if name == "" {
+		return LeakDefault, nil
+	}
+	strictness, ok := leakStrictnessNames[strings.ToLower(name)]
+	if !ok {
+		return LeakDefault, fmt.Errorf("unknown leak strictness '%s' (want off, exact, inflection, stem or strict)", name)
+	}
+	return strictness, nil
+}
+
+// leakCheckedKinds lists the hint kinds whose text can reveal the answer by accident.
+// Letter and pattern hints describe the word's spelling on purpose.
+var leakCheckedKinds = map[HintKind]bool{
+	HintDefinition: true,
+	HintSynonym:    true,
+	HintSoundsLike: true,
+	HintRelated:    true,
+	HintCloze:      true,
+	HintRhyme:      true,
+}
+
+// LeakDetector finds hints that contain the answer or a member of its word family
+type LeakDetector struct {
+	vocabulary *Vocabulary
+	strictness LeakStrictness
+}
+
+// NewLeakDetector creates a detector; vocabulary may be nil when no word families are available
+func NewLeakDetector(vocabulary *Vocabulary, strictness LeakStrictness) *LeakDetector {
+	if strictness == LeakDefault {
+		strictness = LeakStem
+	}
+	return &LeakDetector{
+		vocabulary: vocabulary,
+		strictness: strictness,
+	}
+}
+
+// Strictness returns the strictness used when a call does not override it
+func (ld *LeakDetector) Strictness() LeakStrictness {
+	return ld.strictness
+}
+
+// HintLeaks reports whether the hint gives away the word at the given strictness
+func (ld *LeakDetector) HintLeaks(word string, hint Hint, strictness LeakStrictness) bool {
+	if !leakCheckedKinds[hint.Kind] {
+		return false
+	}
+	return ld.Leaks(word, hintContent(hint.Text), strictness)
+}
+
+// Leaks reports whether text reveals the word at the given strictness.
+// For phrases such as "belong to", the whole phrase (or an inflected form
+// of it) must appear unless strictness is LeakStem or above, in which case
+// any content word of the phrase counts as a leak.
+func (ld *LeakDetector) Leaks(word, text string, strictness LeakStrictness) bool {
+	if strictness == LeakDefault {
+		strictness = ld.strictness
+	}
+	if strictness == LeakOff {
+		return false
+	}
+
+	word = normalizeWord(word)
+	if word == "" {
+		return false
+	}
+	tokens := tokenize(text)
+
+	if !isPhrase(word) {
+		matcher := ld.newTokenMatcher(word, strictness)
+		for _, token := range tokens {
+			if matcher.matches(token) {
+				return true
+			}
+		}
+		return false
+	}
+
+	// Look for the phrase as a run of (possibly inflected) words
+	parts := phraseTokens(word)
+	partStrictness := strictness
+	if partStrictness > LeakInflection {
+		partStrictness = LeakInflection
+	}
+	matchers := make([]*tokenMatcher, len(parts))
+	for i, part := range parts {
+		matchers[i] = ld.newTokenMatcher(part, partStrictness)
+	}
+	for start := 0; start+len(parts) <= len(tokens); start++ {
+		found := true
+		for i, matcher := range matchers {
+			if !matcher.matches(tokens[start+i]) {
+				found = false
+				break
+			}
+		}
+		if found {
+			return true
+		}
+	}
+
+	if strictness < LeakStem {
+		return false
+	}
+
+	// Any content word of the phrase gives too much away at stem strictness
+	for _, part := range contentTokens(word) {
+		matcher := ld.newTokenMatcher(part, strictness)
+		for _, token := range tokens {
+			if matcher.matches(token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// tokenMatcher checks single tokens against one target word
+type tokenMatcher struct {
+	word        string
+	stem        string
+	strictness  LeakStrictness
+	inflections map[string]bool
+	family      map[string]bool
+}
+
+// newTokenMatcher prepares the forms of word that count as a leak at the given strictness
+func (ld *LeakDetector) newTokenMatcher(word string, strictness LeakStrictness) *tokenMatcher {
+	tm := &tokenMatcher{
+		word:       word,
+		stem:       Stem(word),
+		strictness: strictness,
+	}
+
+	if strictness >= LeakInflection {
+		tm.inflections = Inflections(word)
+	}
+
+	if strictness >= LeakStem && ld.vocabulary != nil {
+		tm.family = make(map[string]bool)
+		for _, member := range ld.vocabulary.Family(word) {
+			tm.family[member] = true
+		}
+	}
+
+	return tm
+}
+
+// matches reports whether the token is the target word or a form of it
+func (tm *tokenMatcher) matches(token string) bool {
+	if token == tm.word {
+		return true
+	}
+	if tm.inflections[token] || tm.family[token] {
+		return true
+	}
+	if tm.strictness >= LeakStem && Stem(token) == tm.stem {
+		return true
+	}
+	if tm.strictness >= LeakStrict && len(tm.stem) >= 4 && strings.Contains(token, tm.stem) {
+		return true
+	}
+	return false
+}
+
+// hintContent returns the part of a hint after its label, e.g. "forsake" in "Similar to: forsake"
+func hintContent(text string) string {
+	if i := strings.Index(text, ": "); i >= 0 {
+		return text[i+2:]
+	}
+	return text
+}
+
+// tokenize splits text into lowercase words
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	})
+}
+
+// Inflections returns the regular inflected forms of a word, including the word itself
+func Inflections(word string) map[string]bool {
+	forms := map[string]bool{word: true, word + "'s": true}
+	n := len(word)
+	if n == 0 {
+		return forms
+	}
+
+	last := word[n-1]
+	isVowel := func(c byte) bool { return strings.IndexByte("aeiou", c) >= 0 }
+
+	// Plurals and third person singular
+	switch {
+	case strings.HasSuffix(word, "s"), strings.HasSuffix(word, "x"), strings.HasSuffix(word, "z"),
+		strings.HasSuffix(word, "ch"), strings.HasSuffix(word, "sh"):
+		forms[word+"es"] = true
+	case last == 'y' && n > 1 && !isVowel(word[n-2]):
+		forms[word[:n-1]+"ies"] = true
+	default:
+		forms[word+"s"] = true
+	}
+
+	// Past tense, participles and comparatives
+	base := word
+	switch {
+	case last == 'e':
+		base = word[:n-1]
+	case last == 'y' && n > 1 && !isVowel(word[n-2]):
+		forms[word[:n-1]+"ied"] = true
+		forms[word[:n-1]+"ier"] = true
+		forms[word[:n-1]+"iest"] = true
+		forms[word+"ing"] = true
+		return forms
+	case n >= 3 && !isVowel(last) && isVowel(word[n-2]) && !isVowel(word[n-3]) &&
+		strings.IndexByte("wxy", last) < 0:
+		// Doubled final consonant, e.g. "stop" -> "stopped"
+		doubled := word + string(last)
+		forms[doubled+"ed"] = true
+		forms[doubled+"ing"] = true
+		forms[doubled+"er"] = true
+		forms[doubled+"est"] = true
+	}
+	for _, suffix := range []string{"ed", "ing", "er", "est"} {
+		forms[base+suffix] = true
+	}
+
+	return forms
+}
@@ -0,0 +1,139 @@
+package main
+
+import "testing"
+
+func TestLeaks(t *testing.T) {
+	vocabulary := NewVocabulary([]VocabularyEntry{{Word: "abandon"}, {Word: "abandonment"}, {Word: "happy"}, {Word: "happiness"}})
+	detector := NewLeakDetector(vocabulary, LeakDefault)
+
+	tests := []struct {
+		word       string
+		text       string
+		strictness LeakStrictness
+		want       bool
+	}{
+		// The exact word, whatever its case and punctuation
+		{"abandon", "To abandon a ship", LeakExact, true},
+		{"abandon", "Abandon!", LeakExact, true},
+		{"abandon", "to leave behind", LeakExact, false},
+		{"abandon", "to leave behind", LeakStrict, false},
+		{"abandon", "they abandoned it", LeakExact, false},
+
+		// Inflections
+		{"abandon", "they abandoned it", LeakInflection, true},
+		{"stop", "she stopped", LeakInflection, true},
+		{"city", "two cities", LeakInflection, true},
+		{"bake", "the baker's baking", LeakInflection, true},
+		{"happy", "happier times", LeakInflection, true},
+		{"abandon", "an act of abandonment", LeakInflection, false},
+
+		// Stems and word families
+		{"abandon", "an act of abandonment", LeakStem, true},
+		{"happy", "a state of happiness", LeakStem, true},
+		{"run", "an unrunnable race", LeakStem, false},
+
+		// Strict also catches words containing the stem
+		{"abandon", "an unabandonable post", LeakStrict, true},
+		{"run", "an unrunnable race", LeakStrict, false},
+
+		// Off keeps everything, and the default is the detector's strictness
+		{"abandon", "abandon", LeakOff, false},
+		{"abandon", "an act of abandonment", LeakDefault, true},
+		{"", "anything", LeakStrict, false},
+
+		// Phrases must appear whole below stem strictness
+		{"give up", "never give up", LeakExact, true},
+		{"give up", "she gave up", LeakInflection, false},
+		{"give up", "he is giving up", LeakInflection, true},
+		{"give up", "give it up", LeakInflection, false},
+		{"give up", "a gift to give", LeakInflection, false},
+
+		// At stem strictness any content word of the phrase leaks, but not a stop word
+		{"give up", "a gift to give", LeakStem, true},
+		{"give up", "look up the word", LeakStem, false},
+		{"a couple", "a pair", LeakStem, false},
+		{"a couple", "two of a kind, like a couple", LeakStem, true},
+	}
+	for _, test := range tests {
+		if got := detector.Leaks(test.word, test.text, test.strictness); got != test.want {
+			t.Errorf("Leaks(%q, %q, %d) = %v, want %v", test.word, test.text, test.strictness, got, test.want)
+		}
+	}
+}
+
+func TestHintLeaks(t *testing.T) {
+	detector := NewLeakDetector(nil, LeakStem)
+	tests := []struct {
+		hint Hint
+		want bool
+	}{
+		{NewHint(HintDefinition, "test", "Definition: to abandon something"), true},
+		{NewHint(HintSynonym, "test", "Similar to: forsake"), false},
+		{NewHint(HintCloze, "test", "They abandoned the ___."), true},
+		{NewHint(HintRhyme, "test", "Rhymes with: abandons"), true},
+
+		// The label before ": " is not part of the hint
+		{NewHint(HintRelated, "test", "Abandon: leave"), false},
+
+		// Spelling hints describe the word on purpose
+		{NewHint(HintMaskedPattern, "test", "abandon"), false},
+		{NewHint(HintAnagram, "test", "Unscramble: nodaban"), false},
+	}
+	for _, test := range tests {
+		if got := detector.HintLeaks("abandon", test.hint, LeakDefault); got != test.want {
+			t.Errorf("HintLeaks(%s %q) = %v, want %v", test.hint.Kind, test.hint.Text, got, test.want)
+		}
+	}
+}
+
+func TestInflections(t *testing.T) {
+	tests := []struct {
+		word  string
+		forms []string
+		not   []string
+	}{
+		{"walk", []string{"walk", "walks", "walked", "walking", "walker", "walk's"}, []string{"walkked"}},
+		{"box", []string{"boxes", "boxed", "boxing"}, []string{"boxs", "boxxed"}},
+		{"wish", []string{"wishes"}, []string{"wishs"}},
+		{"carry", []string{"carries", "carried", "carrier", "carriest", "carrying"}, []string{"carrys"}},
+		{"play", []string{"plays", "played", "playing"}, []string{"plaies"}},
+		{"bake", []string{"bakes", "baked", "baking", "baker", "bakest"}, []string{"bakeed"}},
+		{"stop", []string{"stops", "stopped", "stopping", "stopper"}, nil},
+		{"snow", []string{"snowed"}, []string{"snowwed"}},
+	}
+	for _, test := range tests {
+		forms := Inflections(test.word)
+		for _, form := range test.forms {
+			if !forms[form] {
+				t.Errorf("Inflections(%q) lacks %q", test.word, form)
+			}
+		}
+		for _, form := range test.not {
+			if forms[form] {
+				t.Errorf("Inflections(%q) has %q", test.word, form)
+			}
+		}
+	}
+}
+
+func TestParseLeakStrictness(t *testing.T) {
+	tests := []struct {
+		name string
+		want LeakStrictness
+		ok   bool
+	}{
+		{"", LeakDefault, true},
+		{"off", LeakOff, true},
+		{"Exact", LeakExact, true},
+		{"inflection", LeakInflection, true},
+		{"stem", LeakStem, true},
+		{"STRICT", LeakStrict, true},
+		{"loose", LeakDefault, false},
+	}
+	for _, test := range tests {
+		got, err := ParseLeakStrictness(test.name)
+		if got != test.want || (err == nil) != test.ok {
+			t.Errorf("ParseLeakStrictness(%q) = %d, %v", test.name, got, err)
+		}
+	}
+}
@@ -1,103 +1,208 @@
 package main
 
-// import (
-// 	"encoding/json"
-// 	"fmt"
-// 	"net/http"
-// 	"os"
-// 	"strings"
-// )
-
-// // Response structure for the dictionary API
-// type DictionaryResponse struct {
-// 	Word      string `json:"word"`
-// 	Phonetic  string `json:"phonetic,omitempty"`
-// 	Phonetics []struct {
-// 		Text  string `json:"text,omitempty"`
-// 		Audio string `json:"audio,omitempty"`
-// 	} `json:"phonetics,omitempty"`
-// 	Meanings []struct {
-// 		PartOfSpeech string `json:"partOfSpeech,omitempty"`
-// 		Definitions  []struct {
-// 			Definition string   `json:"definition,omitempty"`
-// 			Example    string   `json:"example,omitempty"`
-// 			Synonyms   []string `json:"synonyms,omitempty"`
-// 			Antonyms   []string `json:"antonyms,omitempty"`
-// 		} `json:"definitions,omitempty"`
-// 	} `json:"meanings,omitempty"`
-// }
-
-// // WordExists checks if a word exists in the English dictionary
-// func WordExists(word string) (bool, error) {
-// 	// Clean the word input
-// 	word = strings.TrimSpace(strings.ToLower(word))
-
-// 	// Create the API URL
-// 	url := fmt.Sprintf("https://api.dictionaryapi.dev/api/v2/entries/en/%s", word)
-
-// 	// Make the HTTP request
-// 	resp, err := http.Get(url)
-// 	if err != nil {
-// 		return false, fmt.Errorf("error making HTTP request: %v", err)
-// 	}
-// 	defer resp.Body.Close()
-
-// 	// Check the response status code
-// 	// 200 means the word exists, 404 means it doesn't
-// 	if resp.StatusCode == http.StatusOK {
-// 		return true, nil
-// 	} else if resp.StatusCode == http.StatusNotFound {
-// 		return false, nil
-// 	} else {
-// 		return false, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-// 	}
-// }
-
-// func main() {
-// 	// Check if a word argument was provided
-// 	if len(os.Args) < 2 {
-// 		fmt.Println("Usage: go run dictionary.go <word>")
-// 		os.Exit(1)
-// 	}
-
-// 	// Get the word from command line arguments
-// 	word := os.Args[1]
-
-// 	// Check if the word exists
-// 	exists, err := WordExists(word)
-// 	if err != nil {
-// 		fmt.Printf("Error checking word: %v\n", err)
-// 		os.Exit(1)
-// 	}
-
-// 	// Print the result
-// 	if exists {
-// 		fmt.Printf("The word '%s' exists in the English dictionary.\n", word)
-
-// 		// Optionally get additional information about the word
-// 		url := fmt.Sprintf("https://api.dictionaryapi.dev/api/v2/entries/en/%s", word)
-// 		resp, err := http.Get(url)
-// 		if err == nil && resp.StatusCode == http.StatusOK {
-// 			defer resp.Body.Close()
-
-// 			var dictResp []DictionaryResponse
-// 			if err := json.NewDecoder(resp.Body).Decode(&dictResp); err == nil && len(dictResp) > 0 {
-// 				fmt.Println("\nDefinitions:")
-// 				for _, meaning := range dictResp[0].Meanings {
-// 					fmt.Printf("  Part of speech: %s\n", meaning.PartOfSpeech)
-// 					for i, def := range meaning.Definitions {
-// 						if i >= 2 { // Limit to 2 definitions per part of speech
-// 							break
-// 						}
-// 						fmt.Printf("    - %s\n", def.Definition)
-// 						if def.Example != "" {
-// 							fmt.Printf("      Example: %s\n", def.Example)
-// 						}
-// 					}
-// 				}
-// 			}
-// 		}
-// 	} else {
-// 		fmt.Printf("The word '%s' does not exist in the English dictionary.\n", word)
-// 	}
-// }
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+)
+
+// hintSetupFlags holds the command-line options shared by every command that generates hints
+type hintSetupFlags struct {
+	cacheKind *string
+	cacheDir  *string
+	cacheTTL  *time.Duration
+	vocabPath *string
+	leakName  *string
+	reveal    *string
+}
+
+// addHintSetupFlags registers the hint generator options on a flag set
+func addHintSetupFlags(fs *flag.FlagSet) *hintSetupFlags {
+	return &hintSetupFlags{
+		cacheKind: fs.String("cache", "memory", "DataMuse response cache: none, memory or disk"),
+		cacheDir:  fs.String("cache-dir", ".hintcache", "directory of the on-disk hint cache"),
+		cacheTTL:  fs.Duration("cache-ttl", 30*24*time.Hour, "how long cached responses stay fresh"),
+		vocabPath: fs.String("vocab", "../wordcategorizer/clustered_with_difficulty.json", "categorized vocabulary used for word families and difficulty"),
+		leakName:  fs.String("leak", "stem", "leak strictness: off, exact, inflection, stem or strict"),
+		reveal:    fs.String("reveal", "ends,vowels,consonants", "masked patterns to offer, optionally with a level, e.g. ends,vowels:5,consonants:7"),
+	}
+}
+
+// hintSetup is a configured hint generator together with the resources it uses
+type hintSetup struct {
+	generator   *HintGenerator
+	cache       HintCache
+	vocabulary  *Vocabulary
+	mongoClient *MongoDBClient
+}
+
+// build creates the hint generator described by the flags.
+// The stored word details are used when MONGODB_URI is set.
+func (hf *hintSetupFlags) build() (*hintSetup, error) {
+	strictness, err := ParseLeakStrictness(*hf.leakName)
+	if err != nil {
+		return nil, err
+	}
+
+	reveals, err := ParseLetterReveals(*hf.reveal)
+	if err != nil {
+		return nil, err
+	}
+
+	setup := &hintSetup{}
+
+	setup.vocabulary, err = LoadVocabulary(*hf.vocabPath)
+	if err != nil {
+		log.Printf("Warning: word families unavailable: %v", err)
+	}
+
+	setup.cache, err = newHintCache(*hf.cacheKind, *hf.cacheDir, *hf.cacheTTL)
+	if err != nil {
+		return nil, fmt.Errorf("error creating hint cache: %v", err)
+	}
+
+	sources := []HintSource{}
+	var datamuse HintSource = NewDataMuseHintSource(setup.cache)
+
+	// Stored word details and their example sentences come first. DataMuse
+	// stays a source of its own so it can still be picked or skipped per call,
+	// but it is only asked about words without a stored sentence.
+	if mongoURI := os.Getenv("MONGODB_URI"); mongoURI != "" {
+		setup.mongoClient, err = NewMongoDBClient(mongoURI, "toenglish")
+		if err != nil {
+			return nil, err
+		}
+		cloze := NewClozeHintSource(setup.mongoClient, setup.vocabulary)
+		sources = append(sources, NewMongoHintSource(setup.mongoClient), cloze)
+		datamuse = cloze.Fallback(datamuse)
+	}
+	sources = append(sources, datamuse)
+	// The phonetic index covers our own vocabulary, so it needs the word list or the stored words
+	if setup.vocabulary != nil || setup.mongoClient != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		index, err := LoadPhoneticIndex(ctx, setup.vocabulary, setup.mongoClient)
+		cancel()
+		if err != nil {
+			log.Printf("Warning: phonetic hints unavailable: %v", err)
+		} else {
+			sources = append(sources, NewPhoneticHintSource(index))
+		}
+	}
+
+	patterns := NewPatternHintSource(setup.mongoClient)
+	patterns.Reveals = reveals
+	sources = append(sources, NewOfflineHintSource(), patterns)
+
+	setup.generator = NewHintGenerator(sources...)
+	setup.generator.SetLeakDetector(NewLeakDetector(setup.vocabulary, strictness))
+	if setup.vocabulary != nil {
+		setup.generator.SetDifficultyFilter(NewDifficultyFilter(setup.vocabulary))
+	}
+
+	return setup, nil
+}
+
+// Close releases the MongoDB connection, if any
+func (hs *hintSetup) Close() {
+	if hs.mongoClient == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	hs.mongoClient.Close(ctx)
+}
+
+func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "warm":
+			if err := runWarmCommand(os.Args[2:]); err != nil {
+				log.Fatalf("Error warming hint cache: %v", err)
+			}
+			return
+		case "spell":
+			if err := runSpellCommand(os.Args[2:]); err != nil {
+				log.Fatalf("Error checking spelling: %v", err)
+			}
+			return
+		case "validate":
+			if err := runValidateCommand(os.Args[2:]); err != nil {
+				log.Fatalf("Error validating words: %v", err)
+			}
+			return
+		case "sounds":
+			if err := runSoundsCommand(os.Args[2:]); err != nil {
+				log.Fatalf("Error finding similar sounding words: %v", err)
+			}
+			return
+		case "precompute":
+			if err := runPrecomputeCommand(os.Args[2:]); err != nil {
+				log.Fatalf("Error precomputing hints: %v", err)
+			}
+			return
+		case "crossword":
+			if err := runCrosswordCommand(os.Args[2:]); err != nil {
+				log.Fatalf("Error generating crossword: %v", err)
+			}
+			return
+		case "wordsearch":
+			if err := runWordSearchCommand(os.Args[2:]); err != nil {
+				log.Fatalf("Error generating word search: %v", err)
+			}
+			return
+		case "hangman":
+			if err := runHangmanCommand(os.Args[2:]); err != nil {
+				log.Fatalf("Error generating hangman rounds: %v", err)
+			}
+			return
+		case "serve":
+			if err := runServeCommand(os.Args[2:]); err != nil {
+				log.Fatalf("Error running hint server: %v", err)
+			}
+			return
+		}
+	}
+
+	setupFlags := addHintSetupFlags(flag.CommandLine)
+	flag.Parse()
+
+	setup, err := setupFlags.build()
+	if err != nil {
+		log.Fatalf("Error setting up hint generator: %v", err)
+	}
+	defer setup.Close()
+
+	hintGen := setup.generator
+
+	// Example word to generate hints for
+	word := "contractor"
+	if flag.NArg() > 0 {
+		word = flag.Arg(0)
+	}
+
+	// How many hints you want at max
+	maxHints := 5
+
+	result, err := hintGen.GenerateHints(context.Background(), word, HintOptions{
+		MaxHints: maxHints,
+		Timeout:  5 * time.Second,
+	})
+	if err != nil {
+		log.Fatalf("Error generating hints: %v", err)
+	}
+
+	fmt.Printf("Hints for the word '%s' (sources: %s):\n", word, strings.Join(hintGen.SourceNames(), ", "))
+	for i, hint := range result.Ladder().Rungs {
+		fmt.Printf("%d. [level %d, %s] %s\n", i+1, hint.Level, hint.Kind, hint.Text)
+	}
+	for _, fallback := range result.Fallbacks {
+		fmt.Printf("Note: %s\n", fallback)
+	}
+	for _, sourceErr := range result.Errors {
+		fmt.Printf("Warning: %v\n", sourceErr)
+	}
+}
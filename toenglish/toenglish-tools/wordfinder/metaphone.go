@@ -0,0 +1,168 @@
+package main
+
+import "strings"
+
+// Metaphone returns the phonetic key of a word using Lawrence Philips' original
+// Metaphone rules. Words that sound alike, such as "there" and "their", share a key.
+// Letters other than a-z are ignored, so phrases are encoded as one run of sounds.
+func Metaphone(word string) string {
+	letters := []byte{}
+	for _, r := range strings.ToUpper(word) {
+		if r >= 'A' && r <= 'Z' {
+			letters = append(letters, byte(r))
+		}
+	}
+	if len(letters) == 0 {
+		return ""
+	}
+
+	// Silent or changed initial letters
+	switch {
+	case hasPrefix(letters, "AE"), hasPrefix(letters, "GN"), hasPrefix(letters, "KN"),
+		hasPrefix(letters, "PN"), hasPrefix(letters, "WR"):
+		letters = letters[1:]
+	case letters[0] == 'X':
+		letters[0] = 'S'
+	case hasPrefix(letters, "WH"):
+		letters = append([]byte{'W'}, letters[2:]...)
+	}
+
+	n := len(letters)
+	at := func(i int) byte {
+		if i < 0 || i >= n {
+			return 0
+		}
+		return letters[i]
+	}
+	vowel := func(c byte) bool {
+		return c == 'A' || c == 'E' || c == 'I' || c == 'O' || c == 'U'
+	}
+	frontVowel := func(c byte) bool {
+		return c == 'E' || c == 'I' || c == 'Y'
+	}
+
+	var key strings.Builder
+	for i := 0; i < n; i++ {
+		c := letters[i]
+
+		// Double letters sound once, except for "CC" as in "accent"
+		if c != 'C' && i > 0 && at(i-1) == c {
+			continue
+		}
+
+		switch c {
+		case 'A', 'E', 'I', 'O', 'U':
+			if i == 0 {
+				key.WriteByte(c)
+			}
+		case 'B':
+			// Silent in a final "MB" as in "lamb"
+			if !(i == n-1 && at(i-1) == 'M') {
+				key.WriteByte('B')
+			}
+		case 'C':
+			switch {
+			case at(i+1) == 'I' && at(i+2) == 'A':
+				key.WriteByte('X')
+			case at(i+1) == 'H':
+				if at(i-1) == 'S' {
+					key.WriteByte('K')
+				} else {
+					key.WriteByte('X')
+				}
+				i++
+			case frontVowel(at(i + 1)):
+				// Silent in "SCI", "SCE" and "SCY"
+				if at(i-1) != 'S' {
+					key.WriteByte('S')
+				}
+			default:
+				key.WriteByte('K')
+			}
+		case 'D':
+			if at(i+1) == 'G' && frontVowel(at(i+2)) {
+				key.WriteByte('J')
+				i++
+			} else {
+				key.WriteByte('T')
+			}
+		case 'G':
+			switch {
+			case at(i+1) == 'H' && i+2 < n && !vowel(at(i+2)):
+				// Silent in "GH" before a consonant, as in "night"
+			case at(i+1) == 'H' && i+2 >= n && i > 0:
+				// Silent in a final "GH", as in "though"
+				i++
+			case at(i+1) == 'N' && (i+2 == n || (at(i+2) == 'E' && at(i+3) == 'D' && i+4 == n)):
+				// Silent in a final "GN" or "GNED", as in "sign"
+			case frontVowel(at(i+1)) && at(i-1) != 'G':
+				key.WriteByte('J')
+			default:
+				key.WriteByte('K')
+			}
+		case 'H':
+			// Silent after a vowel with no vowel following, and after C, S, P, T and G
+			if vowel(at(i-1)) && !vowel(at(i+1)) {
+				continue
+			}
+			if strings.IndexByte("CSPTG", at(i-1)) >= 0 && i > 0 {
+				continue
+			}
+			key.WriteByte('H')
+		case 'K':
+			if at(i-1) != 'C' {
+				key.WriteByte('K')
+			}
+		case 'P':
+			if at(i+1) == 'H' {
+				key.WriteByte('F')
+			} else {
+				key.WriteByte('P')
+			}
+		case 'Q':
+			key.WriteByte('K')
+		case 'S':
+			switch {
+			case at(i+1) == 'H':
+				key.WriteByte('X')
+				i++
+			case at(i+1) == 'I' && (at(i+2) == 'O' || at(i+2) == 'A'):
+				key.WriteByte('X')
+			default:
+				key.WriteByte('S')
+			}
+		case 'T':
+			switch {
+			case at(i+1) == 'I' && (at(i+2) == 'O' || at(i+2) == 'A'):
+				key.WriteByte('X')
+			case at(i+1) == 'H':
+				key.WriteByte('0')
+				i++
+			case at(i+1) == 'C' && at(i+2) == 'H':
+				// Silent in "TCH", as in "watch"
+			default:
+				key.WriteByte('T')
+			}
+		case 'V':
+			key.WriteByte('F')
+		case 'W', 'Y':
+			if vowel(at(i + 1)) {
+				key.WriteByte(c)
+			}
+		case 'X':
+			key.WriteString("KS")
+		case 'Z':
+			key.WriteByte('S')
+		default:
+			// F, J, L, M, N and R sound as written
+			key.WriteByte(c)
+		}
+	}
+
+	return key.String()
+}
+
+// hasPrefix reports whether the letters start with prefix
+func hasPrefix(letters []byte, prefix string) bool {
+	return strings.HasPrefix(string(letters), prefix)
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// VocabularyWord represents a word document from MongoDB
+type VocabularyWord struct {
+	ID               primitive.ObjectID `bson:"_id" json:"_id"`
+	Word             string             `bson:"word" json:"word"`
+	WordCategoryID   primitive.ObjectID `bson:"wordCategoryID" json:"wordCategoryID"`
+	WordCategoryName string             `bson:"wordCategoryName" json:"wordCategoryName"`
+	Difficulty       string             `bson:"difficulty" json:"difficulty"`
+	CreatedAt        time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt        time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
+
+// VocabularyWordDetail represents the word analysis written by worddictionarybuilder
+type VocabularyWordDetail struct {
+	ID               primitive.ObjectID `bson:"_id,omitempty" json:"_id,omitempty"`
+	VocabularyWordID primitive.ObjectID `bson:"vocabularyWordID" json:"vocabularyWordID"`
+	Word             string             `bson:"word" json:"word"`
+	PartOfSpeech     string             `bson:"part_of_speech" json:"part_of_speech"`
+	PronunciationIPA string             `bson:"pronunciation_ipa" json:"pronunciation_ipa"`
+	Syllabification  string             `bson:"syllabification" json:"syllabification"`
+	Definition       string             `bson:"definition" json:"definition"`
+	ExampleSentences []string           `bson:"example_sentences" json:"example_sentences"`
+	Synonyms         []string           `bson:"synonyms" json:"synonyms"`
+	Antonyms         []string           `bson:"antonyms" json:"antonyms"`
+	Tags             []string           `bson:"tags" json:"tags"`
+	Frequency        string             `bson:"frequency" json:"frequency"`
+}
+
+// VocabularyWordHints stores the precomputed hints of a vocabulary word
+type VocabularyWordHints struct {
+	ID               primitive.ObjectID `bson:"_id,omitempty" json:"_id,omitempty"`
+	VocabularyWordID primitive.ObjectID `bson:"vocabularyWordID" json:"vocabularyWordID"`
+	Word             string             `bson:"word" json:"word"`
+	Hints            []Hint             `bson:"hints" json:"hints"`
+	Fallbacks        []string           `bson:"fallbacks,omitempty" json:"fallbacks,omitempty"`
+	Sources          []string           `bson:"sources" json:"sources"`
+	CreatedAt        time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt        time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
+
+// MongoDBClient handles MongoDB operations
+type MongoDBClient struct {
+	Client     *mongo.Client
+	Database   *mongo.Database
+	WordsCol   *mongo.Collection
+	DetailsCol *mongo.Collection
+	HintsCol   *mongo.Collection
+}
+
+// NewMongoDBClient creates a new MongoDB client
+func NewMongoDBClient(connectionString, dbName string) (*MongoDBClient, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(connectionString))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to MongoDB: %v", err)
+	}
+
+	// Test the connection
+	if err := client.Ping(ctx, nil); err != nil {
+		return nil, fmt.Errorf("failed to ping MongoDB: %v", err)
+	}
+
+	database := client.Database(dbName)
+
+	return &MongoDBClient{
+		Client:     client,
+		Database:   database,
+		WordsCol:   database.Collection("vocabularywords"),
+		DetailsCol: database.Collection("vocabularyworddetails"),
+		HintsCol:   database.Collection("vocabularywordhints"),
+	}, nil
+}
+
+// GetAllVocabularyWords retrieves all vocabulary words from MongoDB
+func (mc *MongoDBClient) GetAllVocabularyWords(ctx context.Context) ([]VocabularyWord, error) {
+	cursor, err := mc.WordsCol.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find vocabulary words: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var words []VocabularyWord
+	if err := cursor.All(ctx, &words); err != nil {
+		return nil, fmt.Errorf("failed to decode vocabulary words: %v", err)
+	}
+
+	return words, nil
+}
+
+// GetWordDetail retrieves the stored analysis of a word, or nil when there is none
+func (mc *MongoDBClient) GetWordDetail(ctx context.Context, word string) (*VocabularyWordDetail, error) {
+	var detail VocabularyWordDetail
+	err := mc.DetailsCol.FindOne(ctx, bson.M{"word": word}).Decode(&detail)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find word detail for '%s': %v", word, err)
+	}
+	return &detail, nil
+}
+
+// GetAllWordDetails retrieves every stored word analysis
+func (mc *MongoDBClient) GetAllWordDetails(ctx context.Context) ([]VocabularyWordDetail, error) {
+	cursor, err := mc.DetailsCol.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find word details: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var details []VocabularyWordDetail
+	if err := cursor.All(ctx, &details); err != nil {
+		return nil, fmt.Errorf("failed to decode word details: %v", err)
+	}
+
+	return details, nil
+}
+
+// GetHintUpdateTimes returns when the hints of each vocabulary word were last saved
+func (mc *MongoDBClient) GetHintUpdateTimes(ctx context.Context) (map[primitive.ObjectID]time.Time, error) {
+	cursor, err := mc.HintsCol.Find(ctx, bson.M{}, options.Find().SetProjection(bson.M{"vocabularyWordID": 1, "updatedAt": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find existing word hints: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	updated := make(map[primitive.ObjectID]time.Time)
+	for cursor.Next(ctx) {
+		var doc struct {
+			VocabularyWordID primitive.ObjectID `bson:"vocabularyWordID"`
+			UpdatedAt        time.Time          `bson:"updatedAt"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, fmt.Errorf("failed to decode word hints: %v", err)
+		}
+		updated[doc.VocabularyWordID] = doc.UpdatedAt
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read word hints: %v", err)
+	}
+
+	return updated, nil
+}
+
+// SaveWordHints inserts or replaces the hints of a vocabulary word
+func (mc *MongoDBClient) SaveWordHints(ctx context.Context, hints *VocabularyWordHints) error {
+	now := time.Now()
+	update := bson.M{
+		"$set": bson.M{
+			"word":      hints.Word,
+			"hints":     hints.Hints,
+			"fallbacks": hints.Fallbacks,
+			"sources":   hints.Sources,
+			"updatedAt": now,
+		},
+		"$setOnInsert": bson.M{"createdAt": now},
+	}
+
+	_, err := mc.HintsCol.UpdateOne(ctx, bson.M{"vocabularyWordID": hints.VocabularyWordID}, update, options.Update().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to save word hints: %v", err)
+	}
+
+	return nil
+}
+
+// Close closes the MongoDB connection
+func (mc *MongoDBClient) Close(ctx context.Context) error {
+	return mc.Client.Disconnect(ctx)
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// MongoHintSource generates hints from the vocabularyworddetails collection
+type MongoHintSource struct {
+	mongoClient *MongoDBClient
+}
+
+// NewMongoHintSource creates a hint source backed by the stored word details
+func NewMongoHintSource(mongoClient *MongoDBClient) *MongoHintSource {
+	return &MongoHintSource{mongoClient: mongoClient}
+}
+
+// Name returns the name of the source
+func (ms *MongoHintSource) Name() string {
+	return "mongo"
+}
+
+// Hints builds definition, synonym and tag hints from the stored word detail
+func (ms *MongoHintSource) Hints(ctx context.Context, word string) ([]Hint, error) {
+	detail, err := ms.mongoClient.GetWordDetail(ctx, word)
+	if err != nil {
+		return nil, err
+	}
+	if detail == nil {
+		return []Hint{}, nil
+	}
+
+	hints := []Hint{}
+	wordLower := strings.ToLower(word)
+
+	if detail.Definition != "" && !strings.Contains(strings.ToLower(detail.Definition), wordLower) {
+		hints = append(hints, NewHint(HintDefinition, ms.Name(), fmt.Sprintf("Definition: %s", detail.Definition)))
+	}
+
+	// Get top 2 synonyms as hints
+	for i, syn := range detail.Synonyms {
+		if i >= 2 {
+			break
+		}
+		hints = append(hints, NewHint(HintSynonym, ms.Name(), fmt.Sprintf("Similar to: %s", syn)))
+	}
+
+	// Tags describe the topic the word belongs to
+	for i, tag := range detail.Tags {
+		if i >= 2 {
+			break
+		}
+		hints = append(hints, NewHint(HintRelated, ms.Name(), fmt.Sprintf("Think of: %s", tag)))
+	}
+
+	return hints, nil
+}
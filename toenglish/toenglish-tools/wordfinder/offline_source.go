@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// OfflineHintSource generates hints from the word itself without any network calls
+type OfflineHintSource struct{}
+
+// NewOfflineHintSource creates a new offline hint source
+func NewOfflineHintSource() *OfflineHintSource {
+	return &OfflineHintSource{}
+}
+
+// Name returns the name of the source
+func (ofs *OfflineHintSource) Name() string {
+	return "offline"
+}
+
+// Hints returns basic hints about word length and the first and last letter.
+// Phrases get per-word letter counts such as "2 words: 1 and 6 letters".
+func (ofs *OfflineHintSource) Hints(ctx context.Context, word string) ([]Hint, error) {
+	tokens := phraseTokens(word)
+	if len(tokens) == 0 {
+		return []Hint{}, nil
+	}
+
+	first := []rune(tokens[0])
+	last := []rune(tokens[len(tokens)-1])
+
+	if len(tokens) > 1 {
+		hints := []Hint{
+			NewHint(HintLength, ofs.Name(), phraseLengthText(tokens)),
+			NewHint(HintFirstLetter, ofs.Name(), fmt.Sprintf("The first word starts with '%s'", string(first[0]))),
+			NewHint(HintLastLetter, ofs.Name(), fmt.Sprintf("The last word ends with '%s'", string(last[len(last)-1]))),
+		}
+		return hints, nil
+	}
+
+	letters := first
+	hints := []Hint{NewHint(HintLength, ofs.Name(), fmt.Sprintf("This word has %d letters", len(letters)))}
+	hints = append(hints, NewHint(HintFirstLetter, ofs.Name(), fmt.Sprintf("The word starts with '%s'", string(letters[0]))))
+	if len(letters) > 1 {
+		hints = append(hints, NewHint(HintLastLetter, ofs.Name(), fmt.Sprintf("The word ends with '%s'", string(letters[len(letters)-1]))))
+	}
+	return hints, nil
+}
+
+// phraseLengthText describes the letter count of every word, e.g. "2 words: 1 and 6 letters"
+func phraseLengthText(tokens []string) string {
+	counts := make([]string, len(tokens))
+	for i, token := range tokens {
+		counts[i] = strconv.Itoa(letterCount(token))
+	}
+	joined := strings.Join(counts[:len(counts)-1], ", ") + " and " + counts[len(counts)-1]
+	return fmt.Sprintf("%d words: %s letters", len(tokens), joined)
+}
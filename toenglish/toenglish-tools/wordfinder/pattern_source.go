@@ -0,0 +1,281 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// RevealLetters selects which letters a masked pattern shows
+type RevealLetters string
+
+// Supported letter reveals
+const (
+	RevealEnds       RevealLetters = "ends"
+	RevealVowels     RevealLetters = "vowels"
+	RevealConsonants RevealLetters = "consonants"
+)
+
+// revealKinds maps each letter reveal to the kind of hint it produces
+var revealKinds = map[RevealLetters]HintKind{
+	RevealEnds:       HintMaskedPattern,
+	RevealVowels:     HintVowelPattern,
+	RevealConsonants: HintConsonantPattern,
+}
+
+// LetterReveal is one masked pattern hint shown at a given reveal level
+type LetterReveal struct {
+	Letters RevealLetters
+
+	// Level overrides the reveal level of the hint kind when greater than zero
+	Level int
+}
+
+// DefaultLetterReveals shows the first and last letter, then the vowels, then the consonants
+var DefaultLetterReveals = []LetterReveal{
+	{Letters: RevealEnds},
+	{Letters: RevealVowels},
+	{Letters: RevealConsonants},
+}
+
+// ParseLetterReveals parses a list such as "ends,vowels:5,consonants:7"
+func ParseLetterReveals(spec string) ([]LetterReveal, error) {
+	reveals := []LetterReveal{}
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		name, levelText, hasLevel := strings.Cut(part, ":")
+		reveal := LetterReveal{Letters: RevealLetters(strings.ToLower(name))}
+		if _, ok := revealKinds[reveal.Letters]; !ok {
+			return nil, fmt.Errorf("unknown letter reveal '%s' (want ends, vowels or consonants)", name)
+		}
+		if hasLevel {
+			level, err := strconv.Atoi(levelText)
+			if err != nil || level < 1 || level > MaxRevealLevel {
+				return nil, fmt.Errorf("invalid level '%s' for %s (want 1 to %d)", levelText, name, MaxRevealLevel)
+			}
+			reveal.Level = level
+		}
+		reveals = append(reveals, reveal)
+	}
+	return reveals, nil
+}
+
+// PatternHintSource computes letter-pattern, anagram and syllable hints locally.
+// Syllable counts come from the stored syllabification when a MongoDB client
+// is given and are estimated from the spelling otherwise.
+type PatternHintSource struct {
+	mongoClient *MongoDBClient
+
+	// Reveals lists the masked patterns to produce
+	Reveals []LetterReveal
+}
+
+// NewPatternHintSource creates a pattern hint source; mongoClient may be nil
+func NewPatternHintSource(mongoClient *MongoDBClient) *PatternHintSource {
+	return &PatternHintSource{
+		mongoClient: mongoClient,
+		Reveals:     DefaultLetterReveals,
+	}
+}
+
+// Name returns the name of the source
+func (ps *PatternHintSource) Name() string {
+	return "pattern"
+}
+
+// Hints returns the syllable count, the masked patterns and an anagram of the word.
+// A failed syllabification lookup still returns the other hints together with the error.
+func (ps *PatternHintSource) Hints(ctx context.Context, word string) ([]Hint, error) {
+	tokens := phraseTokens(word)
+	if len(tokens) == 0 {
+		return []Hint{}, nil
+	}
+
+	hints := []Hint{}
+
+	syllables, err := ps.syllableCount(ctx, word)
+	if syllables > 0 {
+		hints = append(hints, NewHint(HintSyllables, ps.Name(), syllableText(syllables, len(tokens) > 1)))
+	}
+
+	for _, reveal := range ps.Reveals {
+		pattern, ok := maskPattern(tokens, reveal.Letters)
+		if !ok {
+			continue
+		}
+		hint := NewHint(revealKinds[reveal.Letters], ps.Name(), fmt.Sprintf("Pattern: %s", pattern))
+		if reveal.Level > 0 {
+			hint.Level = reveal.Level
+		}
+		hints = append(hints, hint)
+	}
+
+	if anagram, ok := anagramPhrase(word, tokens); ok {
+		hints = append(hints, NewHint(HintAnagram, ps.Name(), fmt.Sprintf("Unscramble: %s", anagram)))
+	}
+
+	return hints, err
+}
+
+// syllableCount reads the stored syllabification, falling back to an estimate
+func (ps *PatternHintSource) syllableCount(ctx context.Context, word string) (int, error) {
+	var lookupErr error
+	if ps.mongoClient != nil {
+		detail, err := ps.mongoClient.GetWordDetail(ctx, word)
+		if err != nil {
+			lookupErr = err
+		} else if detail != nil {
+			if count := countSyllabification(detail.Syllabification); count > 0 {
+				return count, nil
+			}
+		}
+	}
+
+	count := 0
+	for _, token := range phraseTokens(word) {
+		count += estimateSyllables(token)
+	}
+	return count, lookupErr
+}
+
+// syllableText describes a syllable count, e.g. "It has 3 syllables"
+func syllableText(count int, phrase bool) string {
+	unit := "syllables"
+	if count == 1 {
+		unit = "syllable"
+	}
+	if phrase {
+		return fmt.Sprintf("The phrase has %d %s in total", count, unit)
+	}
+	return fmt.Sprintf("It has %d %s", count, unit)
+}
+
+// countSyllabification counts the syllables of a stored syllabification such as "con-trac-tor"
+func countSyllabification(syllabification string) int {
+	parts := strings.FieldsFunc(syllabification, func(r rune) bool {
+		return r == '-' || r == '·' || r == '•' || r == '.' || r == '|' || unicode.IsSpace(r)
+	})
+	return len(parts)
+}
+
+// estimateSyllables counts the vowel groups of a word, ignoring a silent final 'e'
+func estimateSyllables(token string) int {
+	letters := []rune(strings.ToLower(token))
+	count := 0
+	previousVowel := false
+	for _, letter := range letters {
+		vowel := isVowel(letter) || letter == 'y'
+		if vowel && !previousVowel {
+			count++
+		}
+		previousVowel = vowel
+	}
+
+	// "make" has one syllable but "table" has two
+	n := len(letters)
+	if n > 2 && letters[n-1] == 'e' && !isVowel(letters[n-2]) && !(letters[n-2] == 'l' && !isVowel(letters[n-3])) {
+		count--
+	}
+	if count < 1 {
+		count = 1
+	}
+	return count
+}
+
+// isVowel reports whether a letter is one of a, e, i, o, u
+func isVowel(letter rune) bool {
+	return strings.ContainsRune("aeiou", unicode.ToLower(letter))
+}
+
+// maskPattern masks every word of a phrase separately, keeping the selected letters.
+// It reports false when the pattern would show nothing or everything.
+func maskPattern(tokens []string, letters RevealLetters) (string, bool) {
+	masked := make([]string, len(tokens))
+	shown, hidden := 0, 0
+	for i, token := range tokens {
+		runes := []rune(token)
+		for j, letter := range runes {
+			if !unicode.IsLetter(letter) {
+				continue
+			}
+			if revealsLetter(letters, runes, j) {
+				shown++
+				continue
+			}
+			runes[j] = '_'
+			hidden++
+		}
+		masked[i] = string(runes)
+	}
+	if shown == 0 || hidden == 0 {
+		return "", false
+	}
+	return strings.Join(masked, " "), true
+}
+
+// revealsLetter reports whether the letter at position i stays visible
+func revealsLetter(letters RevealLetters, runes []rune, i int) bool {
+	switch letters {
+	case RevealEnds:
+		return i == 0 || i == len(runes)-1
+	case RevealVowels:
+		return isVowel(runes[i])
+	case RevealConsonants:
+		return !isVowel(runes[i])
+	}
+	return false
+}
+
+// anagramPhrase shuffles the letters of every word with a seed derived from the word,
+// so the same word always gets the same anagram. It reports false when no word
+// can be scrambled.
+func anagramPhrase(word string, tokens []string) (string, bool) {
+	hash := fnv.New64a()
+	hash.Write([]byte(word))
+	random := rand.New(rand.NewSource(int64(hash.Sum64())))
+
+	scrambled := make([]string, len(tokens))
+	changed := false
+	for i, token := range tokens {
+		runes := []rune(token)
+
+		// Only letters move; hyphens and apostrophes stay where they are
+		positions := []int{}
+		for j, letter := range runes {
+			if unicode.IsLetter(letter) {
+				positions = append(positions, j)
+			}
+		}
+		letters := make([]rune, len(positions))
+		for j, position := range positions {
+			letters[j] = runes[position]
+		}
+		random.Shuffle(len(letters), func(a, b int) {
+			letters[a], letters[b] = letters[b], letters[a]
+		})
+		for j, position := range positions {
+			runes[position] = letters[j]
+		}
+
+		// Rotate when the shuffle happened to give back the original word
+		if string(runes) == token && len(letters) > 1 {
+			letters = append(letters[1:], letters[0])
+			for j, position := range positions {
+				runes[position] = letters[j]
+			}
+		}
+		scrambled[i] = string(runes)
+		if scrambled[i] != token {
+			changed = true
+		}
+	}
+	return strings.Join(scrambled, " "), changed
+}
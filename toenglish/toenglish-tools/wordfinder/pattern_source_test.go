@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestMaskPattern(t *testing.T) {
+	tests := []struct {
+		word    string
+		letters RevealLetters
+		want    string
+	}{
+		{"abandon", RevealEnds, "a_____n"},
+		{"abandon", RevealVowels, "a_a__o_"},
+		{"abandon", RevealConsonants, "_b_nd_n"},
+		{"give up", RevealEnds, "g__e up"},
+		{"mother-in-law", RevealEnds, "m_____-__-__w"},
+		{"don't", RevealVowels, "_o_'_"},
+
+		// Nothing hidden, or nothing shown, is no hint
+		{"at", RevealEnds, ""},
+		{"rhythm", RevealVowels, ""},
+		{"eau", RevealConsonants, ""},
+	}
+	for _, test := range tests {
+		got, ok := maskPattern(phraseTokens(test.word), test.letters)
+		if got != test.want || ok != (test.want != "") {
+			t.Errorf("maskPattern(%q, %s) = %q, %v, want %q", test.word, test.letters, got, ok, test.want)
+		}
+	}
+}
+
+func TestAnagramPhrase(t *testing.T) {
+	tests := []struct {
+		word    string
+		changed bool
+	}{
+		{"abandon", true},
+		{"give up", true},
+		{"mother-in-law", true},
+		{"a", false},
+		{"aa", false},
+	}
+	for _, test := range tests {
+		tokens := phraseTokens(test.word)
+		anagram, ok := anagramPhrase(test.word, tokens)
+		if ok != test.changed {
+			t.Errorf("anagramPhrase(%q) = %q, %v, want changed %v", test.word, anagram, ok, test.changed)
+			continue
+		}
+
+		// Same letters per word, same punctuation, and the same result every time
+		words := strings.Split(anagram, " ")
+		for i, token := range tokens {
+			if sortedLetters(words[i]) != sortedLetters(token) {
+				t.Errorf("anagramPhrase(%q) = %q doesn't use the letters of %q", test.word, anagram, token)
+			}
+			if strings.IndexAny(words[i], "-'") != strings.IndexAny(token, "-'") {
+				t.Errorf("anagramPhrase(%q) = %q moved the punctuation", test.word, anagram)
+			}
+		}
+		if again, _ := anagramPhrase(test.word, tokens); again != anagram {
+			t.Errorf("anagramPhrase(%q) gave %q, then %q", test.word, anagram, again)
+		}
+	}
+}
+
+// sortedLetters returns the letters of a word in order
+func sortedLetters(word string) string {
+	runes := []rune(word)
+	slices.Sort(runes)
+	return string(runes)
+}
+
+func TestSyllables(t *testing.T) {
+	tests := []struct {
+		word string
+		want int
+	}{
+		{"cat", 1},
+		{"make", 1},
+		{"table", 2},
+		{"abandon", 3},
+		{"beautiful", 3},
+		{"rhythm", 1},
+		{"happy", 2},
+		{"the", 1},
+	}
+	for _, test := range tests {
+		if got := estimateSyllables(test.word); got != test.want {
+			t.Errorf("estimateSyllables(%q) = %d, want %d", test.word, got, test.want)
+		}
+	}
+
+	stored := []struct {
+		syllabification string
+		want            int
+	}{
+		{"a-ban-don", 3},
+		{"con·trac·tor", 3},
+		{"give up", 2},
+		{"", 0},
+	}
+	for _, test := range stored {
+		if got := countSyllabification(test.syllabification); got != test.want {
+			t.Errorf("countSyllabification(%q) = %d, want %d", test.syllabification, got, test.want)
+		}
+	}
+}
+
+func TestParseLetterReveals(t *testing.T) {
+	tests := []struct {
+		spec string
+		want []LetterReveal
+		ok   bool
+	}{
+		{"ends,vowels:5", []LetterReveal{{Letters: RevealEnds}, {Letters: RevealVowels, Level: 5}}, true},
+		{" Consonants:7 , ", []LetterReveal{{Letters: RevealConsonants, Level: 7}}, true},
+		{"", []LetterReveal{}, true},
+		{"middle", nil, false},
+		{"ends:0", nil, false},
+		{"ends:8", nil, false},
+		{"ends:x", nil, false},
+	}
+	for _, test := range tests {
+		got, err := ParseLetterReveals(test.spec)
+		if (err == nil) != test.ok || !slices.Equal(got, test.want) {
+			t.Errorf("ParseLetterReveals(%q) = %v, %v", test.spec, got, err)
+		}
+	}
+}
+
+func TestPatternHints(t *testing.T) {
+	source := NewPatternHintSource(nil)
+	source.Reveals = []LetterReveal{{Letters: RevealEnds}, {Letters: RevealVowels, Level: 5}}
+	hints, err := source.Hints(context.Background(), "abandon")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		kind  HintKind
+		text  string
+		level int
+	}{
+		{HintSyllables, "It has 3 syllables", 4},
+		{HintMaskedPattern, "Pattern: a_____n", 6},
+		{HintVowelPattern, "Pattern: a_a__o_", 5},
+		{HintAnagram, "Unscramble: ", 7},
+	}
+	if len(hints) != len(want) {
+		t.Fatalf("got %d hints, want %d: %v", len(hints), len(want), hints)
+	}
+	for i, hint := range hints {
+		if hint.Kind != want[i].kind || !strings.HasPrefix(hint.Text, want[i].text) || hint.Level != want[i].level {
+			t.Errorf("hint %d = %s %q level %d, want %s %q level %d", i, hint.Kind, hint.Text, hint.Level, want[i].kind, want[i].text, want[i].level)
+		}
+	}
+
+	phraseHints, _ := source.Hints(context.Background(), "give up")
+	if phraseHints[0].Text != "The phrase has 2 syllables in total" {
+		t.Errorf("phrase syllables = %q", phraseHints[0].Text)
+	}
+}
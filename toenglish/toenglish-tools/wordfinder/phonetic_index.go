@@ -0,0 +1,384 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// ipaVowels are the IPA symbols that form the nucleus of a syllable
+const ipaVowels = "aeiouæɑɒɔəɛɜɪʊʌɐyøœɯɨʉɤ"
+
+// ipaReplacer folds IPA variants so that dictionaries using different conventions compare equal
+var ipaReplacer = strings.NewReplacer(
+	"ɚ", "ər", "ɝ", "ɜr", "ɹ", "r", "ɡ", "g", "ɫ", "l", "ɾ", "t",
+	"/", "", "[", "", "]", "", "(", "", ")", "", "ː", "", ".", "", " ", "", "ˈ", "", "ˌ", "",
+)
+
+// PhoneticMatch is a word that sounds like the queried word
+type PhoneticMatch struct {
+	Word  string  `json:"word"`
+	Score float64 `json:"score"`
+
+	// Homophone is set when both words have the same stored IPA pronunciation
+	Homophone bool `json:"homophone,omitempty"`
+
+	// SoundsSimilar is set when, without IPA, both words share their Metaphone key
+	// and syllable count. That is too coarse to call them homophones: "there"
+	// and "three" share both.
+	SoundsSimilar bool `json:"soundsSimilar,omitempty"`
+}
+
+// phoneticEntry holds the sounds of one indexed word
+type phoneticEntry struct {
+	word       string
+	stem       string
+	key        []rune
+	ipa        []rune
+	ipaRhyme   string
+	spellRhyme string
+	syllables  int
+}
+
+// PhoneticIndex finds rhymes and near-homophones among our vocabulary without any network calls.
+// Words with a stored IPA pronunciation are compared by sound; the others by
+// their Metaphone key and spelling.
+type PhoneticIndex struct {
+	entries []phoneticEntry
+	byWord  map[string]int
+
+	// MinScore is the lowest similarity reported by SoundsLike
+	MinScore float64
+}
+
+// NewPhoneticIndex indexes the words; ipa maps words to their pronunciation and may be nil
+func NewPhoneticIndex(words []string, ipa map[string]string) *PhoneticIndex {
+	pi := &PhoneticIndex{
+		byWord:   make(map[string]int, len(words)),
+		MinScore: 0.75,
+	}
+	for _, word := range words {
+		word = normalizeWord(word)
+		if word == "" {
+			continue
+		}
+		if _, ok := pi.byWord[word]; ok {
+			continue
+		}
+		pi.byWord[word] = len(pi.entries)
+		pi.entries = append(pi.entries, newPhoneticEntry(word, ipa[word]))
+	}
+	return pi
+}
+
+// LoadPhoneticIndex indexes the vocabulary and, when a MongoDB client is given,
+// every stored word together with its pronunciation_ipa
+func LoadPhoneticIndex(ctx context.Context, vocabulary *Vocabulary, mongoClient *MongoDBClient) (*PhoneticIndex, error) {
+	words := []string{}
+	if vocabulary != nil {
+		for _, entry := range vocabulary.Entries() {
+			words = append(words, entry.Word)
+		}
+	}
+
+	ipa := map[string]string{}
+	if mongoClient != nil {
+		details, err := mongoClient.GetAllWordDetails(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, detail := range details {
+			word := normalizeWord(detail.Word)
+			words = append(words, word)
+			if detail.PronunciationIPA != "" {
+				ipa[word] = detail.PronunciationIPA
+			}
+		}
+	}
+
+	return NewPhoneticIndex(words, ipa), nil
+}
+
+// newPhoneticEntry computes the phonetic key and rhyme endings of a word
+func newPhoneticEntry(word, pronunciation string) phoneticEntry {
+	entry := phoneticEntry{
+		word:       word,
+		stem:       Stem(word),
+		key:        []rune(Metaphone(word)),
+		spellRhyme: spellingRhyme(word),
+	}
+	for _, token := range phraseTokens(word) {
+		entry.syllables += estimateSyllables(token)
+	}
+	// Only the first of several listed pronunciations is used
+	variants := strings.FieldsFunc(pronunciation, func(r rune) bool { return r == ',' || r == ';' })
+	if len(variants) > 0 {
+		entry.ipa = []rune(ipaReplacer.Replace(variants[0]))
+		entry.ipaRhyme = ipaRhyme(variants[0])
+	}
+	return entry
+}
+
+// ipaRhyme returns the sounds from the vowel of the last stressed syllable to the end,
+// e.g. "æktər" for "/kənˈtræktər/"
+func ipaRhyme(pronunciation string) string {
+	stressed := pronunciation
+	if i := strings.LastIndex(pronunciation, "ˈ"); i >= 0 {
+		stressed = pronunciation[i:]
+	}
+	sounds := []rune(ipaReplacer.Replace(stressed))
+
+	if strings.Contains(pronunciation, "ˈ") {
+		for i, sound := range sounds {
+			if strings.ContainsRune(ipaVowels, sound) {
+				return string(sounds[i:])
+			}
+		}
+		return ""
+	}
+	return string(sounds[lastVowelGroup(sounds, ipaVowels):])
+}
+
+// spellingRhyme returns the letters from the last vowel group to the end, e.g. "ight" for "night".
+// A silent final 'e' moves the rhyme back one vowel group, so "make" gives "ake".
+func spellingRhyme(word string) string {
+	letters := []rune(strings.ReplaceAll(word, " ", ""))
+	n := len(letters)
+	if n > 2 && letters[n-1] == 'e' && !isVowel(letters[n-2]) {
+		start := lastVowelGroup(letters[:n-1], "aeiouy")
+		return string(letters[start:])
+	}
+	return string(letters[lastVowelGroup(letters, "aeiouy"):])
+}
+
+// lastVowelGroup returns the index where the last run of vowels starts, or 0 when there is none
+func lastVowelGroup(sounds []rune, vowels string) int {
+	i := len(sounds) - 1
+	for i >= 0 && !strings.ContainsRune(vowels, sounds[i]) {
+		i--
+	}
+	if i < 0 {
+		return 0
+	}
+	for i > 0 && strings.ContainsRune(vowels, sounds[i-1]) {
+		i--
+	}
+	return i
+}
+
+// entry returns the indexed entry of a word, computing one for words outside the index
+func (pi *PhoneticIndex) entry(word string) phoneticEntry {
+	word = normalizeWord(word)
+	if i, ok := pi.byWord[word]; ok {
+		return pi.entries[i]
+	}
+	return newPhoneticEntry(word, "")
+}
+
+// similarity scores how alike two words sound, between 0 and 1, and labels the match
+func similarity(a, b phoneticEntry) (float64, PhoneticMatch) {
+	if len(a.ipa) > 0 && len(b.ipa) > 0 {
+		score := editSimilarity(a.ipa, b.ipa)
+		return score, PhoneticMatch{Word: b.word, Score: score, Homophone: score == 1}
+	}
+
+	// Metaphone keys drop the vowels, so "station" and "situation" share a key;
+	// a different number of syllables lowers the score instead
+	keyScore := editSimilarity(a.key, b.key)
+	spellScore := editSimilarity([]rune(a.word), []rune(b.word))
+	score := (keyScore + spellScore) / 2
+	syllableGap := a.syllables - b.syllables
+	if syllableGap < 0 {
+		syllableGap = -syllableGap
+	}
+	score *= max(0, 1-0.1*float64(syllableGap))
+	return score, PhoneticMatch{Word: b.word, Score: score, SoundsSimilar: len(a.key) > 0 && keyScore == 1 && syllableGap == 0}
+}
+
+// SoundsLike returns up to n near-homophones of the word, most similar first.
+// Members of the word's own family, such as plurals, are left out.
+func (pi *PhoneticIndex) SoundsLike(word string, n int) []PhoneticMatch {
+	target := pi.entry(word)
+	matches := []PhoneticMatch{}
+	for _, candidate := range pi.entries {
+		if candidate.word == target.word || candidate.stem == target.stem {
+			continue
+		}
+		score, match := similarity(target, candidate)
+		if score < pi.MinScore {
+			continue
+		}
+		matches = append(matches, match)
+	}
+	return topMatches(matches, n)
+}
+
+// Rhymes returns up to n words sharing the word's rhyme, scored by how alike the whole words sound
+func (pi *PhoneticIndex) Rhymes(word string, n int) []PhoneticMatch {
+	target := pi.entry(word)
+	matches := []PhoneticMatch{}
+	for _, candidate := range pi.entries {
+		if candidate.word == target.word || candidate.stem == target.stem {
+			continue
+		}
+
+		rhymes := false
+		if target.ipaRhyme != "" && candidate.ipaRhyme != "" {
+			rhymes = target.ipaRhyme == candidate.ipaRhyme
+		} else {
+			rhymes = len([]rune(target.spellRhyme)) >= 2 && target.spellRhyme == candidate.spellRhyme
+		}
+		if !rhymes {
+			continue
+		}
+
+		_, match := similarity(target, candidate)
+		matches = append(matches, match)
+	}
+	return topMatches(matches, n)
+}
+
+// topMatches sorts matches by score, then alphabetically, and keeps the first n
+func topMatches(matches []PhoneticMatch, n int) []PhoneticMatch {
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].Word < matches[j].Word
+	})
+	if n > 0 && len(matches) > n {
+		matches = matches[:n]
+	}
+	return matches
+}
+
+// editSimilarity turns the Levenshtein distance of two sequences into a score between 0 and 1
+func editSimilarity(a, b []rune) float64 {
+	longest := len(a)
+	if len(b) > longest {
+		longest = len(b)
+	}
+	if longest == 0 {
+		return 0
+	}
+	return 1 - float64(levenshtein(a, b))/float64(longest)
+}
+
+// levenshtein counts the insertions, deletions and substitutions that turn a into b
+func levenshtein(a, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+// PhoneticHintSource gives offline "sounds like" and rhyme hints from the phonetic index
+type PhoneticHintSource struct {
+	index *PhoneticIndex
+}
+
+// NewPhoneticHintSource creates a hint source backed by the phonetic index
+func NewPhoneticHintSource(index *PhoneticIndex) *PhoneticHintSource {
+	return &PhoneticHintSource{index: index}
+}
+
+// Name returns the name of the source
+func (phs *PhoneticHintSource) Name() string {
+	return "phonetic"
+}
+
+// Hints returns the two closest near-homophones and the two closest other rhymes
+func (phs *PhoneticHintSource) Hints(ctx context.Context, word string) ([]Hint, error) {
+	hints := []Hint{}
+	used := map[string]bool{}
+	for _, match := range phs.index.SoundsLike(word, 2) {
+		used[match.Word] = true
+		hints = append(hints, NewHint(HintSoundsLike, phs.Name(), fmt.Sprintf("Sounds like: %s", match.Word)))
+	}
+
+	rhymes := 0
+	for _, match := range phs.index.Rhymes(word, 4) {
+		if used[match.Word] || rhymes >= 2 {
+			continue
+		}
+		rhymes++
+		hints = append(hints, NewHint(HintRhyme, phs.Name(), fmt.Sprintf("Rhymes with: %s", match.Word)))
+	}
+	return hints, nil
+}
+
+// runSoundsCommand lists rhymes and near-homophones, e.g. to find confusable words for exercises
+func runSoundsCommand(args []string) error {
+	fs := flag.NewFlagSet("sounds", flag.ExitOnError)
+	vocabPath := fs.String("vocab", "../wordcategorizer/clustered_with_difficulty.json", "categorized vocabulary to index")
+	limit := fs.Int("n", 10, "number of matches to show")
+	minScore := fs.Float64("min-score", 0.75, "lowest similarity of a near-homophone")
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		return fmt.Errorf("usage: wordfinder sounds [-n 10] <word>...")
+	}
+
+	vocabulary, err := LoadVocabulary(*vocabPath)
+	if err != nil {
+		return err
+	}
+
+	var mongoClient *MongoDBClient
+	if mongoURI := os.Getenv("MONGODB_URI"); mongoURI != "" {
+		client, err := NewMongoDBClient(mongoURI, "toenglish")
+		if err != nil {
+			return err
+		}
+		defer client.Close(context.Background())
+		mongoClient = client
+	}
+
+	index, err := LoadPhoneticIndex(context.Background(), vocabulary, mongoClient)
+	if err != nil {
+		return err
+	}
+	index.MinScore = *minScore
+
+	for _, word := range fs.Args() {
+		fmt.Printf("'%s' (key %s)\n", word, Metaphone(word))
+		fmt.Println("  Sounds like:")
+		for _, match := range index.SoundsLike(word, *limit) {
+			printPhoneticMatch(match)
+		}
+		fmt.Println("  Rhymes:")
+		for _, match := range index.Rhymes(word, *limit) {
+			printPhoneticMatch(match)
+		}
+	}
+	return nil
+}
+
+// printPhoneticMatch prints one match with its score
+func printPhoneticMatch(match PhoneticMatch) {
+	switch {
+	case match.Homophone:
+		fmt.Printf("    - %s (%.2f, homophone)\n", match.Word, match.Score)
+		return
+	case match.SoundsSimilar:
+		fmt.Printf("    - %s (%.2f, sounds similar)\n", match.Word, match.Score)
+		return
+	}
+	fmt.Printf("    - %s (%.2f)\n", match.Word, match.Score)
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+func TestMetaphone(t *testing.T) {
+	tests := []struct {
+		word string
+		want string
+	}{
+		// Homophones share a key
+		{"there", "0R"},
+		{"their", "0R"},
+		{"knight", "NT"},
+		{"night", "NT"},
+		{"wright", "RT"},
+
+		// Silent and changed letters
+		{"gnome", "NM"},
+		{"xenon", "SNN"},
+		{"whale", "WL"},
+		{"phone", "FN"},
+		{"dumb", "TM"},
+		{"laugh", "L"},
+		{"school", "SKL"},
+		{"science", "SNS"},
+		{"cherry", "XR"},
+		{"judge", "JJ"},
+		{"action", "AKXN"},
+		{"box", "BKS"},
+
+		// Case, phrases and words without letters
+		{"Thomas", "0MS"},
+		{"give up", "JFP"},
+		{"42", ""},
+		{"", ""},
+	}
+	for _, test := range tests {
+		if got := Metaphone(test.word); got != test.want {
+			t.Errorf("Metaphone(%q) = %q, want %q", test.word, got, test.want)
+		}
+	}
+}
+
+func TestIPARhyme(t *testing.T) {
+	tests := []struct {
+		pronunciation string
+		want          string
+	}{
+		{"/kənˈtræktər/", "æktər"},
+		{"ˈhæpi", "æpi"},
+		{"/naɪt/", "aɪt"},
+		{"[ˈbɝd]", "ɜrd"},
+		{"/bɛər/", "ɛər"},
+	}
+	for _, test := range tests {
+		if got := ipaRhyme(test.pronunciation); got != test.want {
+			t.Errorf("ipaRhyme(%q) = %q, want %q", test.pronunciation, got, test.want)
+		}
+	}
+}
+
+func TestSpellingRhyme(t *testing.T) {
+	tests := []struct {
+		word string
+		want string
+	}{
+		{"night", "ight"},
+		{"make", "ake"},
+		{"rhythm", "ythm"},
+		{"tree", "ee"},
+	}
+	for _, test := range tests {
+		if got := spellingRhyme(test.word); got != test.want {
+			t.Errorf("spellingRhyme(%q) = %q, want %q", test.word, got, test.want)
+		}
+	}
+}
+
+func newTestPhoneticIndex() *PhoneticIndex {
+	words := []string{"there", "their", "three", "knight", "night", "nights", "light", "sight", "write", "right", "bear", "bare", "care"}
+	ipa := map[string]string{
+		"bear":  "/bɛər/",
+		"bare":  "/bɛər/, /bɛr/",
+		"care":  "/kɛər/",
+		"write": "/raɪt/",
+		"right": "/raɪt/",
+	}
+	return NewPhoneticIndex(words, ipa)
+}
+
+func TestSoundsLike(t *testing.T) {
+	index := newTestPhoneticIndex()
+	tests := []struct {
+		word    string
+		matches []PhoneticMatch
+	}{
+		// Same IPA is a homophone
+		{"bear", []PhoneticMatch{{Word: "bare", Score: 1, Homophone: true}, {Word: "care", Score: 0.75}}},
+		{"write", []PhoneticMatch{{Word: "right", Score: 1, Homophone: true}}},
+
+		// Without IPA a shared Metaphone key and syllable count only sound similar,
+		// and the word's own family ("nights") is left out
+		{"knight", []PhoneticMatch{{Word: "night", Score: 11.0 / 12, SoundsSimilar: true}}},
+		{"there", []PhoneticMatch{{Word: "their", Score: 0.8, SoundsSimilar: true}, {Word: "three", Score: 0.8, SoundsSimilar: true}}},
+	}
+	for _, test := range tests {
+		got := index.SoundsLike(test.word, 5)
+		if len(got) != len(test.matches) {
+			t.Errorf("SoundsLike(%q) = %v, want %v", test.word, got, test.matches)
+			continue
+		}
+		for i, match := range got {
+			want := test.matches[i]
+			if match.Word != want.Word || match.Homophone != want.Homophone || match.SoundsSimilar != want.SoundsSimilar || match.Score < want.Score-0.001 || match.Score > want.Score+0.001 {
+				t.Errorf("SoundsLike(%q)[%d] = %+v, want %+v", test.word, i, match, want)
+			}
+		}
+	}
+}
+
+func TestRhymes(t *testing.T) {
+	index := newTestPhoneticIndex()
+	tests := []struct {
+		word string
+		want []string
+	}{
+		{"night", []string{"knight", "light", "right", "sight"}},
+		{"bear", []string{"bare", "care"}},
+		{"three", nil},
+	}
+	for _, test := range tests {
+		got := []string{}
+		for _, match := range index.Rhymes(test.word, 4) {
+			got = append(got, match.Word)
+		}
+		if len(got) != len(test.want) {
+			t.Errorf("Rhymes(%q) = %v, want %v", test.word, got, test.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != test.want[i] {
+				t.Errorf("Rhymes(%q) = %v, want %v", test.word, got, test.want)
+				break
+			}
+		}
+	}
+}
+
+func TestPhoneticHints(t *testing.T) {
+	hints, err := NewPhoneticHintSource(newTestPhoneticIndex()).Hints(context.Background(), "night")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// "knight" is only given once, as the closest sound
+	want := []string{"Sounds like: knight", "Rhymes with: light", "Rhymes with: right"}
+	if len(hints) != len(want) {
+		t.Fatalf("got %v, want %v", hints, want)
+	}
+	for i, hint := range hints {
+		if hint.Text != want[i] {
+			t.Errorf("hint %d = %q, want %q", i, hint.Text, want[i])
+		}
+	}
+}
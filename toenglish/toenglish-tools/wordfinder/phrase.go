@@ -0,0 +1,60 @@
+package main
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// phraseStopWords are function words that carry no meaning on their own inside a phrase
+var phraseStopWords = map[string]bool{
+	"a": true, "an": true, "the": true, "to": true, "of": true, "in": true,
+	"on": true, "at": true, "by": true, "for": true, "with": true, "and": true,
+	"or": true, "up": true, "out": true, "off": true, "as": true, "be": true,
+}
+
+// normalizeWord lowercases a word or phrase and collapses its whitespace
+func normalizeWord(word string) string {
+	return strings.Join(strings.Fields(strings.ToLower(word)), " ")
+}
+
+// isPhrase reports whether the normalized word is a multi-word expression
+func isPhrase(word string) bool {
+	return strings.Contains(word, " ")
+}
+
+// phraseTokens splits a phrase into its words
+func phraseTokens(word string) []string {
+	return strings.Fields(word)
+}
+
+// contentTokens returns the words of a phrase that are not stop words.
+// A phrase made only of stop words returns all of its words.
+func contentTokens(word string) []string {
+	tokens := phraseTokens(word)
+	content := []string{}
+	for _, token := range tokens {
+		if !phraseStopWords[token] {
+			content = append(content, token)
+		}
+	}
+	if len(content) == 0 {
+		return tokens
+	}
+	return content
+}
+
+// headToken returns the longest content word of a phrase, e.g. "couple" in "a couple"
+func headToken(word string) string {
+	head := ""
+	for _, token := range contentTokens(word) {
+		if utf8.RuneCountInString(token) > utf8.RuneCountInString(head) {
+			head = token
+		}
+	}
+	return head
+}
+
+// letterCount returns the number of letters in a single word, counting runes rather than bytes
+func letterCount(token string) int {
+	return utf8.RuneCountInString(token)
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jdkato/prose/v2"
+)
+
+// POSTagChecker uses the prose part-of-speech tagger to describe words.
+// Prose tags almost any unknown token as a noun, so its tags are a signal
+// for the report only: the verdict is always unknown, and only the
+// dictionary or the spell checker can confirm a word.
+type POSTagChecker struct{}
+
+// NewPOSTagChecker creates a new part-of-speech checker
+func NewPOSTagChecker() *POSTagChecker {
+	return &POSTagChecker{}
+}
+
+// Name returns the name of the checker
+func (pc *POSTagChecker) Name() string {
+	return "pos-tagger"
+}
+
+// Check tags every token of the word and reports the parts of speech without a verdict
+func (pc *POSTagChecker) Check(ctx context.Context, word string) (CheckResult, error) {
+	doc, err := prose.NewDocument(word, prose.WithSegmentation(false), prose.WithExtraction(false))
+	if err != nil {
+		return CheckResult{}, fmt.Errorf("error analyzing word: %v", err)
+	}
+
+	tags := []string{}
+	for _, token := range doc.Tokens() {
+		if token.Tag == "XX" || token.Tag == "" {
+			return CheckResult{Checker: pc.Name(), Verdict: VerdictUnknown}, nil
+		}
+		tags = append(tags, describeTag(token.Tag))
+	}
+
+	return CheckResult{Checker: pc.Name(), Verdict: VerdictUnknown, POSTags: tags}, nil
+}
+
+// describeTag returns a description of the POS tag
+func describeTag(tag string) string {
+	tags := map[string]string{
+		"CC":   "coordinating conjunction",
+		"CD":   "cardinal number",
+		"DT":   "determiner",
+		"EX":   "existential there",
+		"FW":   "foreign word",
+		"IN":   "preposition/subordinating conjunction",
+		"JJ":   "adjective",
+		"JJR":  "adjective, comparative",
+		"JJS":  "adjective, superlative",
+		"LS":   "list item marker",
+		"MD":   "modal",
+		"NN":   "noun, singular or mass",
+		"NNS":  "noun, plural",
+		"NNP":  "proper noun, singular",
+		"NNPS": "proper noun, plural",
+		"PDT":  "predeterminer",
+		"POS":  "possessive ending",
+		"PRP":  "personal pronoun",
+		"PRP$": "possessive pronoun",
+		"RB":   "adverb",
+		"RBR":  "adverb, comparative",
+		"RBS":  "adverb, superlative",
+		"RP":   "particle",
+		"SYM":  "symbol",
+		"TO":   "to",
+		"UH":   "interjection",
+		"VB":   "verb, base form",
+		"VBD":  "verb, past tense",
+		"VBG":  "verb, gerund/present participle",
+		"VBN":  "verb, past participle",
+		"VBP":  "verb, non-3rd person singular present",
+		"VBZ":  "verb, 3rd person singular present",
+		"WDT":  "wh-determiner",
+		"WP":   "wh-pronoun",
+		"WP$":  "possessive wh-pronoun",
+		"WRB":  "wh-adverb",
+	}
+
+	if desc, ok := tags[tag]; ok {
+		return desc
+	}
+	return "unknown word type"
+}
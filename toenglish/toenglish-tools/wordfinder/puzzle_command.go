@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// puzzleWordFlags choose the words of a word search or hangman puzzle
+type puzzleWordFlags struct {
+	categoriesPath *string
+	clustersPath   *string
+	categoryName   *string
+	difficulty     *int
+	seed           *int64
+	out            *string
+	answers        *bool
+}
+
+// addPuzzleWordFlags registers the word selection and output flags on a command's flag set
+func addPuzzleWordFlags(fs *flag.FlagSet, out string) *puzzleWordFlags {
+	return &puzzleWordFlags{
+		categoriesPath: fs.String("categories", "../wordcategorizer/cluster_data.json", "category names written by wordcategorizer"),
+		clustersPath:   fs.String("clusters", "", "take the words from the \"Cluster N\" groups of this clusters.json instead of the vocabulary"),
+		categoryName:   fs.String("category", "", "category id, label (\"Cluster 3\") or name; empty uses every category"),
+		difficulty:     fs.Int("difficulty", 0, "difficulty level 1-3; 0 uses every level"),
+		seed:           fs.Int64("seed", time.Now().UnixNano(), "random seed; reuse it to get the same puzzle"),
+		out:            fs.String("out", out, "output file name without extension"),
+		answers:        fs.Bool("answers", false, "also write an answer key"),
+	}
+}
+
+// puzzleWords are the words selected for a puzzle
+type puzzleWords struct {
+	entries []VocabularyEntry
+	title   string
+	label   string
+}
+
+// selectEntries picks the words of the category and difficulty, from clusters.json
+// when -clusters is set and from the vocabulary otherwise. Cluster words only have
+// a difficulty when the vocabulary knows them.
+func (pf *puzzleWordFlags) selectEntries(title string, vocabulary *Vocabulary) (*puzzleWords, error) {
+	if *pf.categoryName == "" && *pf.difficulty == 0 {
+		return nil, fmt.Errorf("choose the words with -category <name> and/or -difficulty <1-3>")
+	}
+
+	words := &puzzleWords{title: title}
+	if *pf.categoryName != "" {
+		categories, err := LoadCategories(*pf.categoriesPath)
+		if err != nil {
+			return nil, err
+		}
+		category, err := categories.Find(*pf.categoryName)
+		if err != nil {
+			return nil, err
+		}
+		words.label = category.Label()
+		words.title = category.Name
+	}
+	if *pf.difficulty > 0 {
+		words.title = fmt.Sprintf("%s (level %d)", words.title, *pf.difficulty)
+	}
+
+	if *pf.clustersPath == "" {
+		if vocabulary == nil {
+			return nil, fmt.Errorf("a vocabulary is required to pick words without -clusters")
+		}
+		words.entries = SelectWords(vocabulary, words.label, *pf.difficulty)
+		return words, nil
+	}
+
+	clusters, err := LoadClusterWords(*pf.clustersPath)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range clusters.Entries(words.label) {
+		if vocabulary != nil {
+			if known, ok := vocabulary.Lookup(entry.Word); ok {
+				entry.Difficulty = known.Difficulty
+			}
+		}
+		if *pf.difficulty > 0 && entry.Difficulty != *pf.difficulty {
+			continue
+		}
+		words.entries = append(words.entries, entry)
+	}
+	return words, nil
+}
+
+// writePuzzle writes the puzzle as <out>.json and <out>.html, and <out>_answers.html with -answers
+func (pf *puzzleWordFlags) writePuzzle(puzzle any, render func(w io.Writer, showAnswers bool) error) error {
+	data, err := json.MarshalIndent(puzzle, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding puzzle: %v", err)
+	}
+	if err := os.WriteFile(*pf.out+".json", data, 0644); err != nil {
+		return fmt.Errorf("error writing puzzle: %v", err)
+	}
+
+	if err := writePuzzleHTML(*pf.out+".html", render, false); err != nil {
+		return err
+	}
+	if *pf.answers {
+		return writePuzzleHTML(*pf.out+"_answers.html", render, true)
+	}
+	return nil
+}
+
+// writePuzzleHTML renders a puzzle page into a file
+func writePuzzleHTML(path string, render func(w io.Writer, showAnswers bool) error, showAnswers bool) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("error creating %s: %v", path, err)
+	}
+	defer file.Close()
+
+	return render(file, showAnswers)
+}
+
+// runWordSearchCommand generates a word search grid for a category or difficulty level
+func runWordSearchCommand(args []string) error {
+	fs := flag.NewFlagSet("wordsearch", flag.ExitOnError)
+	vocabPath := fs.String("vocab", "../wordcategorizer/clustered_with_difficulty.json", "categorized vocabulary to pick words from")
+	size := fs.Int("size", DefaultWordSearchOptions.Width, "width and height of the grid")
+	directions := fs.String("directions", "right,down,down-right", "directions words may read in; \"forward\" or \"all\" for shortcuts")
+	minWords := fs.Int("min-words", DefaultWordSearchOptions.MinWords, "fewest words to hide")
+	maxWords := fs.Int("max-words", DefaultWordSearchOptions.MaxWords, "most words to hide")
+	decoyLetters := fs.String("decoy-letters", "", "letters filling the free cells; empty uses the letters of the hidden words")
+	decoys := fs.Int("decoys", DefaultWordSearchOptions.Decoys, "number of near-miss words to plant")
+	wordFlags := addPuzzleWordFlags(fs, "wordsearch")
+	fs.Parse(args)
+
+	vocabulary, err := LoadVocabulary(*vocabPath)
+	if err != nil && *wordFlags.clustersPath == "" {
+		return err
+	}
+	words, err := wordFlags.selectEntries("Word Search", vocabulary)
+	if err != nil {
+		return err
+	}
+
+	opts := DefaultWordSearchOptions
+	if opts.Directions, err = ParseWordSearchDirections(*directions); err != nil {
+		return err
+	}
+	opts.Width = *size
+	opts.Height = *size
+	opts.MinWords = *minWords
+	opts.MaxWords = *maxWords
+	opts.DecoyLetters = *decoyLetters
+	opts.Decoys = *decoys
+	opts.Seed = *wordFlags.seed
+
+	fmt.Printf("Building a word search from %d words...\n", len(words.entries))
+	search, err := GenerateWordSearch(words.entries, opts)
+	if err != nil {
+		return err
+	}
+	search.Title = words.title
+	search.Category = words.label
+	search.Difficulty = *wordFlags.difficulty
+
+	render := func(w io.Writer, showAnswers bool) error {
+		return RenderWordSearchHTML(w, search, showAnswers)
+	}
+	if err := wordFlags.writePuzzle(search, render); err != nil {
+		return err
+	}
+
+	fmt.Printf("Hid %d words on a %dx%d grid (seed %d), written to %s.json and %s.html\n",
+		len(search.Words), search.Width, search.Height, search.Seed, *wordFlags.out, *wordFlags.out)
+	return nil
+}
+
+// runHangmanCommand generates hangman rounds for a category or difficulty level
+func runHangmanCommand(args []string) error {
+	fs := flag.NewFlagSet("hangman", flag.ExitOnError)
+	rounds := fs.Int("rounds", DefaultHangmanOptions.Rounds, "number of words")
+	maxWrong := fs.Int("max-wrong", DefaultHangmanOptions.MaxWrong, "wrong letters allowed per word")
+	wordFlags := addPuzzleWordFlags(fs, "hangman")
+	setupFlags := addHintSetupFlags(fs)
+	fs.Parse(args)
+
+	setup, err := setupFlags.build()
+	if err != nil {
+		return err
+	}
+	defer setup.Close()
+
+	words, err := wordFlags.selectEntries("Hangman", setup.vocabulary)
+	if err != nil {
+		return err
+	}
+
+	opts := DefaultHangmanOptions
+	opts.Rounds = *rounds
+	opts.MaxWrong = *maxWrong
+	opts.Seed = *wordFlags.seed
+
+	fmt.Printf("Picking hangman words from %d words...\n", len(words.entries))
+	set, err := NewHangmanGenerator(setup.generator).Generate(context.Background(), words.entries, opts)
+	if err != nil {
+		return err
+	}
+	set.Title = words.title
+	set.Category = words.label
+	set.Difficulty = *wordFlags.difficulty
+
+	render := func(w io.Writer, showAnswers bool) error {
+		return RenderHangmanHTML(w, set, showAnswers)
+	}
+	if err := wordFlags.writePuzzle(set, render); err != nil {
+		return err
+	}
+
+	fmt.Printf("Wrote %d hangman rounds (seed %d) to %s.json and %s.html\n",
+		len(set.Rounds), set.Seed, *wordFlags.out, *wordFlags.out)
+	return nil
+}
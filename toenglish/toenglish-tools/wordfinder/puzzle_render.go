@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+)
+
+// wordSearchTemplate renders a printable grid with the list of words to find
+var wordSearchTemplate = template.Must(template.New("wordsearch").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+  body { font-family: Georgia, serif; margin: 2em; color: #222; }
+  h1 { font-size: 1.6em; margin-bottom: 0.2em; }
+  .meta { color: #666; margin-bottom: 1.5em; }
+  table { border-collapse: collapse; font-family: monospace; font-size: 1.3em; }
+  td { width: 1.6em; height: 1.6em; text-align: center; }
+  td.found { background: #ffe08a; font-weight: bold; }
+  .words { columns: 3; margin-top: 1.5em; list-style: none; padding-left: 0; }
+  @media print { body { margin: 0.5in; } }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<div class="meta">{{if .Category}}{{.Category}} · {{end}}{{if .Difficulty}}Difficulty {{.Difficulty}} · {{end}}{{len .Words}} words</div>
+<table>{{range .Rows}}<tr>{{range .}}<td{{if .Found}} class="found"{{end}}>{{.Letter}}</td>{{end}}</tr>{{end}}</table>
+<ul class="words">{{range .Words}}<li>{{.Word}}</li>{{end}}</ul>
+</body>
+</html>
+`))
+
+// wordSearchCell is one letter of the rendered grid
+type wordSearchCell struct {
+	Letter string
+	Found  bool
+}
+
+// wordSearchPage is the data passed to wordSearchTemplate
+type wordSearchPage struct {
+	*WordSearch
+	Rows [][]wordSearchCell
+}
+
+// RenderWordSearchHTML writes a printable HTML page of the puzzle.
+// With showAnswers the hidden words are highlighted, for an answer key.
+func RenderWordSearchHTML(w io.Writer, search *WordSearch, showAnswers bool) error {
+	page := wordSearchPage{WordSearch: search, Rows: make([][]wordSearchCell, len(search.Grid))}
+	for row, line := range search.Grid {
+		page.Rows[row] = make([]wordSearchCell, len(line))
+		for col := 0; col < len(line); col++ {
+			page.Rows[row][col] = wordSearchCell{Letter: string(line[col])}
+		}
+	}
+	if showAnswers {
+		for _, hidden := range search.Words {
+			step := wordSearchSteps[hidden.Direction]
+			for i := 0; i < len(hidden.Word); i++ {
+				page.Rows[hidden.Row+i*step[0]][hidden.Col+i*step[1]].Found = true
+			}
+		}
+	}
+
+	if err := wordSearchTemplate.Execute(w, page); err != nil {
+		return fmt.Errorf("error rendering word search: %v", err)
+	}
+	return nil
+}
+
+// hangmanTemplate renders one card per round with the pattern, the clue and room for wrong letters
+var hangmanTemplate = template.Must(template.New("hangman").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+  body { font-family: Georgia, serif; margin: 2em; color: #222; }
+  h1 { font-size: 1.6em; margin-bottom: 0.2em; }
+  .meta { color: #666; margin-bottom: 1.5em; }
+  .round { border: 1px solid #ccc; padding: 0.8em 1em; margin-bottom: 1em; page-break-inside: avoid; }
+  .pattern { font-family: monospace; font-size: 1.6em; letter-spacing: 0.3em; }
+  .clue { margin-top: 0.4em; }
+  .wrong { margin-top: 0.6em; color: #666; }
+  .wrong span { display: inline-block; width: 1.4em; border-bottom: 1px solid #999; margin-right: 0.4em; }
+  .answer { margin-top: 0.4em; font-weight: bold; }
+  @media print { body { margin: 0.5in; } }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<div class="meta">{{if .Category}}{{.Category}} · {{end}}{{if .Difficulty}}Difficulty {{.Difficulty}} · {{end}}{{len .Rounds}} rounds</div>
+{{range .Cards}}<div class="round">
+  <div><b>{{.Number}}.</b> <span class="pattern">{{.Shown}}</span></div>
+  {{if .Clue}}<div class="clue">{{.Clue}}</div>{{end}}
+  <div class="wrong">Wrong letters: {{range .Blanks}}<span>&nbsp;</span>{{end}}</div>
+  {{if .ShowAnswer}}<div class="answer">{{.Answer}}</div>{{end}}
+</div>
+{{end}}</body>
+</html>
+`))
+
+// hangmanCard is one rendered round
+type hangmanCard struct {
+	HangmanRound
+	Shown      string
+	Blanks     []struct{}
+	ShowAnswer bool
+}
+
+// hangmanPage is the data passed to hangmanTemplate
+type hangmanPage struct {
+	*HangmanSet
+	Cards []hangmanCard
+}
+
+// RenderHangmanHTML writes a printable HTML page of the rounds.
+// With showAnswers every card also shows its answer, for an answer key.
+func RenderHangmanHTML(w io.Writer, set *HangmanSet, showAnswers bool) error {
+	page := hangmanPage{HangmanSet: set}
+	for _, round := range set.Rounds {
+		page.Cards = append(page.Cards, hangmanCard{
+			HangmanRound: round,
+			Shown:        strings.ToUpper(round.Pattern),
+			Blanks:       make([]struct{}, round.MaxWrong),
+			ShowAnswer:   showAnswers,
+		})
+	}
+
+	if err := hangmanTemplate.Execute(w, page); err != nil {
+		return fmt.Errorf("error rendering hangman rounds: %v", err)
+	}
+	return nil
+}
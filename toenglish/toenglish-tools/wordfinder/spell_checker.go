@@ -0,0 +1,205 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/sajari/fuzzy"
+)
+
+// SpellChecker checks words offline against a fuzzy model trained on our own corpus
+type SpellChecker struct {
+	model *fuzzy.Model
+}
+
+// NewSpellChecker creates an untrained spell checker
+func NewSpellChecker() *SpellChecker {
+	model := fuzzy.NewModel()
+
+	// Index every word we train, even if it appears only once, and allow two edits
+	model.SetThreshold(1)
+	model.SetDepth(2)
+
+	return &SpellChecker{model: model}
+}
+
+// LoadSpellChecker reads a model file written by Save
+func LoadSpellChecker(path string) (*SpellChecker, error) {
+	model, err := fuzzy.Load(path)
+	if err != nil {
+		return nil, fmt.Errorf("error loading spell model: %v", err)
+	}
+	return &SpellChecker{model: model}, nil
+}
+
+// Save writes the trained model to a file
+func (sc *SpellChecker) Save(path string) error {
+	if err := sc.model.Save(path); err != nil {
+		return fmt.Errorf("error saving spell model: %v", err)
+	}
+	return nil
+}
+
+// TrainWords adds words and phrases to the model, one count per occurrence
+func (sc *SpellChecker) TrainWords(words []string) {
+	terms := []string{}
+	for _, word := range words {
+		terms = append(terms, tokenize(word)...)
+	}
+	sc.model.Train(terms)
+}
+
+// TrainText adds every word of a sentence or longer text to the model
+func (sc *SpellChecker) TrainText(text string) {
+	sc.model.Train(tokenize(text))
+}
+
+// Check reports whether every word of the input is known to the model
+func (sc *SpellChecker) Check(input string) bool {
+	tokens := tokenize(input)
+	if len(tokens) == 0 {
+		return false
+	}
+	for _, token := range tokens {
+		if sc.model.SpellCheck(token) != token {
+			return false
+		}
+	}
+	return true
+}
+
+// Suggest returns up to n likely corrections for the input, best first.
+// For phrases only the misspelled words are replaced.
+func (sc *SpellChecker) Suggest(input string, n int) []string {
+	tokens := tokenize(input)
+	if len(tokens) == 0 || n <= 0 {
+		return []string{}
+	}
+
+	if len(tokens) == 1 {
+		suggestions := sc.model.SpellCheckSuggestions(tokens[0], n)
+		if suggestions == nil {
+			return []string{}
+		}
+		return suggestions
+	}
+
+	// Correct each unknown word of a phrase with its best suggestion
+	corrected := make([]string, len(tokens))
+	changed := false
+	for i, token := range tokens {
+		corrected[i] = token
+		if sc.model.SpellCheck(token) == token {
+			continue
+		}
+		if best := sc.model.SpellCheckSuggestions(token, 1); len(best) > 0 {
+			corrected[i] = best[0]
+			changed = true
+		}
+	}
+	if !changed {
+		return []string{}
+	}
+	return []string{strings.Join(corrected, " ")}
+}
+
+// TrainSpellCorpus trains the checker on the Oxford word list and, when a
+// MongoDB client is given, on the stored words and their example sentences.
+// It returns the number of texts the model was trained on.
+func TrainSpellCorpus(ctx context.Context, sc *SpellChecker, oxfordPath string, mongoClient *MongoDBClient) (int, error) {
+	words, err := readWordList(oxfordPath)
+	if err != nil {
+		return 0, err
+	}
+	sc.TrainWords(words)
+	count := len(words)
+
+	if mongoClient == nil {
+		return count, nil
+	}
+
+	vocabularyWords, err := mongoClient.GetAllVocabularyWords(ctx)
+	if err != nil {
+		return count, err
+	}
+	for _, word := range vocabularyWords {
+		sc.TrainWords([]string{word.Word})
+		count++
+	}
+
+	details, err := mongoClient.GetAllWordDetails(ctx)
+	if err != nil {
+		return count, err
+	}
+	for _, detail := range details {
+		sc.TrainWords([]string{detail.Word})
+		count++
+		for _, sentence := range detail.ExampleSentences {
+			sc.TrainText(sentence)
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// runSpellCommand trains a spell model or checks words against a saved one
+func runSpellCommand(args []string) error {
+	fs := flag.NewFlagSet("spell", flag.ExitOnError)
+	modelPath := fs.String("model", "spell_model.json", "spell model file")
+	train := fs.Bool("train", false, "train a new model and save it to -model")
+	oxfordPath := fs.String("words", "../wordcategorizer/oxford3000_clean.txt", "word list used for training")
+	limit := fs.Int("n", 5, "number of suggestions to show")
+	fs.Parse(args)
+
+	if *train {
+		var mongoClient *MongoDBClient
+		if mongoURI := os.Getenv("MONGODB_URI"); mongoURI != "" {
+			client, err := NewMongoDBClient(mongoURI, "toenglish")
+			if err != nil {
+				return err
+			}
+			defer client.Close(context.Background())
+			mongoClient = client
+		}
+
+		sc := NewSpellChecker()
+		count, err := TrainSpellCorpus(context.Background(), sc, *oxfordPath, mongoClient)
+		if err != nil {
+			return err
+		}
+		if err := sc.Save(*modelPath); err != nil {
+			return err
+		}
+		fmt.Printf("Trained spell model on %d texts and saved it to %s\n", count, *modelPath)
+		return nil
+	}
+
+	if fs.NArg() == 0 {
+		return fmt.Errorf("usage: wordfinder spell [-model file] <word>...")
+	}
+
+	sc, err := LoadSpellChecker(*modelPath)
+	if err != nil {
+		return err
+	}
+
+	for _, word := range fs.Args() {
+		if sc.Check(word) {
+			fmt.Printf("The word '%s' exists in the dictionary.\n", word)
+			continue
+		}
+
+		fmt.Printf("The word '%s' does not exist in the dictionary.\n", word)
+		if suggestions := sc.Suggest(word, *limit); len(suggestions) > 0 {
+			fmt.Println("Did you mean:")
+			for _, suggestion := range suggestions {
+				fmt.Printf("  - %s\n", suggestion)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// newTestSpellChecker trains a checker on a small corpus where "necessary" is seen only once
+func newTestSpellChecker() *SpellChecker {
+	spell := NewSpellChecker()
+	spell.TrainWords([]string{"separate", "separate", "definitely", "necessary", "give up", "receive"})
+	spell.TrainText("We receive the parcel and separate it.")
+	return spell
+}
+
+func TestSpellCheckerCheck(t *testing.T) {
+	spell := newTestSpellChecker()
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		// A word trained once is known, since the threshold is 1
+		{"necessary", true},
+		{"Separate", true},
+		{"give up", true},
+		{"parcel", true},
+		{"seperate", false},
+		{"give upp", false},
+		{"unknown", false},
+		{"", false},
+		{"!!", false},
+	}
+	for _, test := range tests {
+		if got := spell.Check(test.input); got != test.want {
+			t.Errorf("Check(%q) = %v, want %v", test.input, got, test.want)
+		}
+	}
+}
+
+func TestSpellCheckerSuggest(t *testing.T) {
+	spell := newTestSpellChecker()
+	tests := []struct {
+		input string
+		want  string
+	}{
+		// One and two edits away are corrected, since the depth is 2
+		{"seperate", "separate"},
+		{"definately", "definitely"},
+		{"neccesary", "necessary"},
+		{"recieve", "receive"},
+		{"giv upp", "give up"},
+
+		// Three edits are too far
+		{"defnatly", ""},
+		{"xyz", ""},
+	}
+	for _, test := range tests {
+		suggestions := spell.Suggest(test.input, 3)
+		if test.want == "" {
+			if len(suggestions) != 0 {
+				t.Errorf("Suggest(%q) = %q, want none", test.input, suggestions)
+			}
+			continue
+		}
+		if !slices.Contains(suggestions, test.want) {
+			t.Errorf("Suggest(%q) = %q, want %q among them", test.input, suggestions, test.want)
+		}
+	}
+
+	if suggestions := spell.Suggest("seperate", 0); len(suggestions) != 0 {
+		t.Errorf("Suggest with n = 0 returned %q", suggestions)
+	}
+	if suggestions := spell.Suggest("give up", 3); len(suggestions) != 0 {
+		t.Errorf("Suggest of a correct phrase returned %q", suggestions)
+	}
+}
+
+func TestSpellCheckerSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spell_model.json")
+	if err := newTestSpellChecker().Save(path); err != nil {
+		t.Fatal(err)
+	}
+	spell, err := LoadSpellChecker(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !spell.Check("necessary") || !slices.Contains(spell.Suggest("seperate", 3), "separate") {
+		t.Error("the loaded model lost its words")
+	}
+}
+
+func TestSpellModelChecker(t *testing.T) {
+	checker := NewSpellModelChecker(newTestSpellChecker())
+	tests := []struct {
+		word       string
+		verdict    Verdict
+		confidence float64
+	}{
+		{"separate", VerdictValid, 0.7},
+		{"seperate", VerdictInvalid, 0.5},
+	}
+	for _, test := range tests {
+		result, err := checker.Check(context.Background(), test.word)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Verdict != test.verdict || result.Confidence != test.confidence {
+			t.Errorf("Check(%q) = %s (%.2f), want %s (%.2f)", test.word, result.Verdict, result.Confidence, test.verdict, test.confidence)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package main
+
+import "strings"
+
+// Stem reduces an English word to its Porter stem, so that "abandon",
+// "abandoned" and "abandonment" all map to "abandon".
+// Words that are not plain lowercase ASCII letters are returned unchanged.
+func Stem(word string) string {
+	word = strings.ToLower(word)
+	if len(word) <= 2 {
+		return word
+	}
+	for i := 0; i < len(word); i++ {
+		if word[i] < 'a' || word[i] > 'z' {
+			return word
+		}
+	}
+
+	s := &porterStemmer{b: []byte(word)}
+	s.step1a()
+	s.step1b()
+	s.step1c()
+	s.step2()
+	s.step3()
+	s.step4()
+	s.step5()
+	return string(s.b)
+}
+
+// porterStemmer holds the word being stemmed
+type porterStemmer struct {
+	b []byte
+}
+
+// isConsonant reports whether the letter at i is a consonant
+func (s *porterStemmer) isConsonant(i int) bool {
+	switch s.b[i] {
+	case 'a', 'e', 'i', 'o', 'u':
+		return false
+	case 'y':
+		return i == 0 || !s.isConsonant(i-1)
+	}
+	return true
+}
+
+// measure counts the VC sequences in b[:end]
+func (s *porterStemmer) measure(end int) int {
+	m := 0
+	i := 0
+	for i < end && s.isConsonant(i) {
+		i++
+	}
+	for i < end {
+		for i < end && !s.isConsonant(i) {
+			i++
+		}
+		if i >= end {
+			break
+		}
+		for i < end && s.isConsonant(i) {
+			i++
+		}
+		m++
+	}
+	return m
+}
+
+// hasVowel reports whether b[:end] contains a vowel
+func (s *porterStemmer) hasVowel(end int) bool {
+	for i := 0; i < end; i++ {
+		if !s.isConsonant(i) {
+			return true
+		}
+	}
+	return false
+}
+
+// endsDoubleConsonant reports whether b[:end] ends with a double consonant
+func (s *porterStemmer) endsDoubleConsonant(end int) bool {
+	return end >= 2 && s.b[end-1] == s.b[end-2] && s.isConsonant(end-1)
+}
+
+// endsCVC reports whether b[:end] ends consonant-vowel-consonant where the
+// last consonant is not w, x or y
+func (s *porterStemmer) endsCVC(end int) bool {
+	if end < 3 || !s.isConsonant(end-1) || s.isConsonant(end-2) || !s.isConsonant(end-3) {
+		return false
+	}
+	switch s.b[end-1] {
+	case 'w', 'x', 'y':
+		return false
+	}
+	return true
+}
+
+// hasSuffix reports whether the word ends with suffix
+func (s *porterStemmer) hasSuffix(suffix string) bool {
+	return strings.HasSuffix(string(s.b), suffix)
+}
+
+// replaceSuffix swaps suffix for replacement when the remaining stem has measure > minMeasure
+func (s *porterStemmer) replaceSuffix(suffix, replacement string, minMeasure int) bool {
+	if !s.hasSuffix(suffix) {
+		return false
+	}
+	stemEnd := len(s.b) - len(suffix)
+	if s.measure(stemEnd) > minMeasure {
+		s.b = append(s.b[:stemEnd], replacement...)
+	}
+	return true
+}
+
+// step1a removes plurals
+func (s *porterStemmer) step1a() {
+	switch {
+	case s.hasSuffix("sses"):
+		s.b = s.b[:len(s.b)-2]
+	case s.hasSuffix("ies"):
+		s.b = s.b[:len(s.b)-2]
+	case s.hasSuffix("ss"):
+	case s.hasSuffix("s"):
+		s.b = s.b[:len(s.b)-1]
+	}
+}
+
+// step1b removes -ed and -ing
+func (s *porterStemmer) step1b() {
+	if s.hasSuffix("eed") {
+		if s.measure(len(s.b)-3) > 0 {
+			s.b = s.b[:len(s.b)-1]
+		}
+		return
+	}
+
+	removed := false
+	for _, suffix := range []string{"ed", "ing"} {
+		if s.hasSuffix(suffix) && s.hasVowel(len(s.b)-len(suffix)) {
+			s.b = s.b[:len(s.b)-len(suffix)]
+			removed = true
+			break
+		}
+	}
+	if !removed {
+		return
+	}
+
+	switch {
+	case s.hasSuffix("at"), s.hasSuffix("bl"), s.hasSuffix("iz"):
+		s.b = append(s.b, 'e')
+	case s.endsDoubleConsonant(len(s.b)):
+		switch s.b[len(s.b)-1] {
+		case 'l', 's', 'z':
+		default:
+			s.b = s.b[:len(s.b)-1]
+		}
+	case s.measure(len(s.b)) == 1 && s.endsCVC(len(s.b)):
+		s.b = append(s.b, 'e')
+	}
+}
+
+// step1c turns a terminal y into i when there is another vowel in the stem
+func (s *porterStemmer) step1c() {
+	if s.hasSuffix("y") && s.hasVowel(len(s.b)-1) {
+		s.b[len(s.b)-1] = 'i'
+	}
+}
+
+// step2 maps double suffixes to single ones
+func (s *porterStemmer) step2() {
+	rules := [][2]string{
+		{"ational", "ate"}, {"tional", "tion"}, {"enci", "ence"}, {"anci", "ance"},
+		{"izer", "ize"}, {"bli", "ble"}, {"alli", "al"}, {"entli", "ent"},
+		{"eli", "e"}, {"ousli", "ous"}, {"ization", "ize"}, {"ation", "ate"},
+		{"ator", "ate"}, {"alism", "al"}, {"iveness", "ive"}, {"fulness", "ful"},
+		{"ousness", "ous"}, {"aliti", "al"}, {"iviti", "ive"}, {"biliti", "ble"},
+		{"logi", "log"},
+	}
+	for _, rule := range rules {
+		if s.replaceSuffix(rule[0], rule[1], 0) {
+			return
+		}
+	}
+}
+
+// step3 removes -ic-, -full, -ness and similar suffixes
+func (s *porterStemmer) step3() {
+	rules := [][2]string{
+		{"icate", "ic"}, {"ative", ""}, {"alize", "al"}, {"iciti", "ic"},
+		{"ical", "ic"}, {"ful", ""}, {"ness", ""},
+	}
+	for _, rule := range rules {
+		if s.replaceSuffix(rule[0], rule[1], 0) {
+			return
+		}
+	}
+}
+
+// step4 removes -ant, -ence, -ment and similar suffixes from longer stems
+func (s *porterStemmer) step4() {
+	suffixes := []string{
+		"al", "ance", "ence", "er", "ic", "able", "ible", "ant", "ement",
+		"ment", "ent", "ion", "ou", "ism", "ate", "iti", "ous", "ive", "ize",
+	}
+	for _, suffix := range suffixes {
+		if !s.hasSuffix(suffix) {
+			continue
+		}
+		stemEnd := len(s.b) - len(suffix)
+		if suffix == "ion" && (stemEnd == 0 || (s.b[stemEnd-1] != 's' && s.b[stemEnd-1] != 't')) {
+			continue
+		}
+		if s.measure(stemEnd) > 1 {
+			s.b = s.b[:stemEnd]
+		}
+		return
+	}
+}
+
+// step5 removes a final -e and reduces a final -ll
+func (s *porterStemmer) step5() {
+	if s.hasSuffix("e") {
+		stemEnd := len(s.b) - 1
+		m := s.measure(stemEnd)
+		if m > 1 || (m == 1 && !s.endsCVC(stemEnd)) {
+			s.b = s.b[:stemEnd]
+		}
+	}
+	if s.hasSuffix("ll") && s.measure(len(s.b)) > 1 {
+		s.b = s.b[:len(s.b)-1]
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import "testing"
+
+func TestStem(t *testing.T) {
+	tests := []struct {
+		word string
+		want string
+	}{
+		// Step 1a: plurals
+		{"caresses", "caress"},
+		{"ponies", "poni"},
+		{"ties", "ti"},
+		{"caress", "caress"},
+		{"cats", "cat"},
+
+		// Step 1b: -eed, -ed and -ing
+		{"feed", "feed"},
+		{"agreed", "agre"},
+		{"plastered", "plaster"},
+		{"bled", "bled"},
+		{"motoring", "motor"},
+		{"sing", "sing"},
+		{"conflated", "conflat"},
+		{"troubled", "troubl"},
+		{"sized", "size"},
+		{"hopping", "hop"},
+		{"tanned", "tan"},
+		{"falling", "fall"},
+		{"hissing", "hiss"},
+		{"fizzed", "fizz"},
+		{"failing", "fail"},
+		{"filing", "file"},
+
+		// Step 1c: y to i
+		{"happy", "happi"},
+		{"sky", "sky"},
+
+		// Steps 2 to 4: derivational suffixes
+		{"relational", "relat"},
+		{"conditional", "condit"},
+		{"rational", "ration"},
+		{"digitizer", "digit"},
+		{"operator", "oper"},
+		{"feudalism", "feudal"},
+		{"triplicate", "triplic"},
+		{"formative", "form"},
+		{"revival", "reviv"},
+		{"allowance", "allow"},
+		{"inference", "infer"},
+		{"adjustment", "adjust"},
+		{"dependent", "depend"},
+		{"adoption", "adopt"},
+
+		// Step 5: final -e and -ll
+		{"probate", "probat"},
+		{"rate", "rate"},
+		{"cease", "ceas"},
+		{"controlling", "control"},
+		{"roll", "roll"},
+
+		// A word family shares its stem
+		{"abandon", "abandon"},
+		{"abandoned", "abandon"},
+		{"abandonment", "abandon"},
+		{"generalizations", "gener"},
+		{"oscillators", "oscil"},
+
+		// Short, mixed case and non-ASCII words
+		{"is", "is"},
+		{"Running", "run"},
+		{"café", "café"},
+		{"don't", "don't"},
+		{"", ""},
+	}
+	for _, test := range tests {
+		if got := Stem(test.word); got != test.want {
+			t.Errorf("Stem(%q) = %q, want %q", test.word, got, test.want)
+		}
+	}
+}
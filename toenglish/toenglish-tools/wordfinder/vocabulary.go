@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// VocabularyEntry represents a word from wordcategorizer/clustered_with_difficulty.json
+type VocabularyEntry struct {
+	Word            string `json:"word"`
+	Category        string `json:"category"`
+	Difficulty      int    `json:"difficulty"`
+	DifficultyLabel string `json:"difficulty_label"`
+}
+
+// Vocabulary indexes the categorized word list by word and by stem
+type Vocabulary struct {
+	entries  []VocabularyEntry
+	byWord   map[string]VocabularyEntry
+	families map[string][]string
+}
+
+// LoadVocabulary reads the categorized word list written by wordcategorizer
+func LoadVocabulary(path string) (*Vocabulary, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading vocabulary file: %v", err)
+	}
+
+	var entries []VocabularyEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("error parsing vocabulary file: %v", err)
+	}
+
+	return NewVocabulary(entries), nil
+}
+
+// NewVocabulary builds the word and word-family indexes for the entries.
+// A word family here is a stem group: the words that the Porter stemmer
+// reduces to the same stem, such as "happy" and "happiness". Derivations
+// that change the stem, such as "decide" and "decision", are not linked.
+func NewVocabulary(entries []VocabularyEntry) *Vocabulary {
+	v := &Vocabulary{
+		entries:  entries,
+		byWord:   make(map[string]VocabularyEntry, len(entries)),
+		families: make(map[string][]string),
+	}
+
+	for _, entry := range entries {
+		word := strings.ToLower(strings.TrimSpace(entry.Word))
+		entry.Word = word
+		v.byWord[word] = entry
+
+		stem := Stem(word)
+		v.families[stem] = append(v.families[stem], word)
+	}
+
+	for stem := range v.families {
+		sort.Strings(v.families[stem])
+	}
+
+	return v
+}
+
+// Lookup returns the entry for the word
+func (v *Vocabulary) Lookup(word string) (VocabularyEntry, bool) {
+	entry, ok := v.byWord[strings.ToLower(word)]
+	return entry, ok
+}
+
+// Entries returns every word of the vocabulary in file order
+func (v *Vocabulary) Entries() []VocabularyEntry {
+	return v.entries
+}
+
+// Family returns the vocabulary words that share a Porter stem with the word
+func (v *Vocabulary) Family(word string) []string {
+	return v.families[Stem(strings.ToLower(word))]
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestVocabularyFamily(t *testing.T) {
+	vocabulary := NewVocabulary([]VocabularyEntry{
+		{Word: "Happy"}, {Word: "happiness"}, {Word: "decide"}, {Word: "decision"}, {Word: "abandon"}, {Word: "abandonment"},
+	})
+	tests := []struct {
+		word string
+		want string
+	}{
+		{"happy", "happiness|happy"},
+		{"HAPPINESS", "happiness|happy"},
+		{"abandoned", "abandon|abandonment"},
+
+		// Families are stem groups, so derivations that change the stem stay apart
+		{"decide", "decide"},
+		{"decision", "decision"},
+		{"unknown", ""},
+	}
+	for _, test := range tests {
+		if got := strings.Join(vocabulary.Family(test.word), "|"); got != test.want {
+			t.Errorf("Family(%q) = %s, want %s", test.word, got, test.want)
+		}
+	}
+}
@@ -1,235 +1,221 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"log"
-	"net/http"
-	"strings"
+	"sync"
 	"time"
 )
 
-// DataMuseWord represents a single word response from DataMuse API
-type DataMuseWord struct {
-	Word  string   `json:"word"`
-	Score int      `json:"score"`
-	Tags  []string `json:"tags,omitempty"`
-	Defs  []string `json:"defs,omitempty"`
-}
-
 // HintGenerator provides methods to generate hints for words
 type HintGenerator struct {
-	httpClient *http.Client
+	sources    []HintSource
+	leaks      *LeakDetector
+	difficulty *DifficultyFilter
 }
 
-// NewHintGenerator creates a new hint generator with proper timeout
-func NewHintGenerator() *HintGenerator {
+// NewHintGenerator creates a new hint generator that queries the sources in priority order
+func NewHintGenerator(sources ...HintSource) *HintGenerator {
+	if len(sources) == 0 {
+		sources = []HintSource{NewDataMuseHintSource(nil), NewOfflineHintSource(), NewPatternHintSource(nil)}
+	}
 	return &HintGenerator{
-		httpClient: &http.Client{
-			Timeout: 5 * time.Second,
-		},
+		sources: sources,
+		leaks:   NewLeakDetector(nil, LeakDefault),
 	}
 }
 
-// GenerateHints creates a list of hints for the given word
-func (hg *HintGenerator) GenerateHints(word string, maxHints int) ([]string, error) {
-	hints := []string{}
-	word = strings.ToLower(word)
-
-	// 1. Get definition hints
-	defHints, err := hg.getDefinitionHints(word)
-	if err == nil {
-		hints = append(hints, defHints...)
-	}
+// SetLeakDetector replaces the detector used to drop hints that give away the word
+func (hg *HintGenerator) SetLeakDetector(leaks *LeakDetector) {
+	hg.leaks = leaks
+}
 
-	// 2. Get synonym hints
-	if len(hints) < maxHints {
-		synHints, err := hg.getSynonymHints(word)
-		if err == nil {
-			hints = append(hints, synHints...)
-		}
-	}
+// SetDifficultyFilter enables filtering hint vocabulary by the target word's difficulty
+func (hg *HintGenerator) SetDifficultyFilter(difficulty *DifficultyFilter) {
+	hg.difficulty = difficulty
+}
 
-	// 3. Get "sounds like" hints
-	if len(hints) < maxHints {
-		soundHints, err := hg.getSoundsLikeHints(word)
-		if err == nil {
-			hints = append(hints, soundHints...)
-		}
+// SourceNames returns the names of the configured sources in priority order
+func (hg *HintGenerator) SourceNames() []string {
+	names := make([]string, len(hg.sources))
+	for i, source := range hg.sources {
+		names[i] = source.Name()
 	}
+	return names
+}
 
-	// 4. Get "related words" hints
-	if len(hints) < maxHints {
-		relatedHints, err := hg.getRelatedWordHints(word)
-		if err == nil {
-			hints = append(hints, relatedHints...)
-		}
+// selectSources resolves source names to configured sources, keeping the given order
+func (hg *HintGenerator) selectSources(names []string) ([]HintSource, error) {
+	if len(names) == 0 {
+		return hg.sources, nil
 	}
 
-	// Add basic hints about word length and first/last letter for very difficult words
-	if len(hints) < 2 {
-		hints = append(hints, fmt.Sprintf("This word has %d letters", len(word)))
-		if len(word) > 0 {
-			hints = append(hints, fmt.Sprintf("The word starts with '%s'", string(word[0])))
+	selected := make([]HintSource, 0, len(names))
+	for _, name := range names {
+		found := false
+		for _, source := range hg.sources {
+			if source.Name() == name {
+				selected = append(selected, source)
+				found = true
+				break
+			}
 		}
-		if len(word) > 1 {
-			hints = append(hints, fmt.Sprintf("The word ends with '%s'", string(word[len(word)-1])))
+		if !found {
+			return nil, fmt.Errorf("unknown hint source '%s'", name)
 		}
 	}
-
-	// Limit the number of hints
-	if len(hints) > maxHints {
-		hints = hints[:maxHints]
-	}
-
-	return hints, nil
+	return selected, nil
 }
 
-// getDefinitionHints gets definition-based hints
-func (hg *HintGenerator) getDefinitionHints(word string) ([]string, error) {
-	url := fmt.Sprintf("https://api.datamuse.com/words?sp=%s&md=d", word)
+// HintOptions controls a single GenerateHints call
+type HintOptions struct {
+	// MaxHints limits the number of hints returned, keeping the least revealing ones
+	MaxHints int
 
-	resp, err := hg.httpClient.Get(url)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
+	// Sources lists the source names to query in priority order.
+	// When empty, the generator's configured order is used.
+	Sources []string
 
-	var words []DataMuseWord
-	if err := json.NewDecoder(resp.Body).Decode(&words); err != nil {
-		return nil, err
-	}
+	// MaxLevel drops hints more revealing than this level when greater than zero
+	MaxLevel int
 
-	hints := []string{}
-	wordLower := strings.ToLower(word)
-
-	// Process definition hints
-	for _, w := range words {
-		if strings.ToLower(w.Word) == wordLower && len(w.Defs) > 0 {
-			for _, def := range w.Defs {
-				// Clean the definition (remove part of speech prefix like "n\t")
-				parts := strings.SplitN(def, "\t", 2)
-				if len(parts) == 2 {
-					cleanDef := parts[1]
-					// Check that the definition doesn't contain the original word
-					if !strings.Contains(strings.ToLower(cleanDef), wordLower) {
-						hints = append(hints, fmt.Sprintf("Definition: %s", cleanDef))
-						if len(hints) >= 2 {
-							break
-						}
-					}
-				}
-			}
-			break
-		}
-	}
+	// LeakStrictness overrides the leak detector's strictness for this call
+	LeakStrictness LeakStrictness
 
-	return hints, nil
-}
+	// Difficulty overrides the target word's difficulty level when greater than zero
+	Difficulty int
 
-// getSynonymHints gets synonym-based hints
-func (hg *HintGenerator) getSynonymHints(word string) ([]string, error) {
-	url := fmt.Sprintf("https://api.datamuse.com/words?rel_syn=%s", word)
+	// Timeout bounds the whole call when greater than zero
+	Timeout time.Duration
+}
 
-	resp, err := hg.httpClient.Get(url)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
+// SourceError reports a failure from a single hint source
+type SourceError struct {
+	Source  string `json:"source"`
+	Message string `json:"error"`
+	Err     error  `json:"-"`
+}
 
-	var words []DataMuseWord
-	if err := json.NewDecoder(resp.Body).Decode(&words); err != nil {
-		return nil, err
-	}
+// Error implements the error interface
+func (se SourceError) Error() string {
+	return fmt.Sprintf("%s: %v", se.Source, se.Err)
+}
 
-	hints := []string{}
+// Unwrap returns the underlying source error
+func (se SourceError) Unwrap() error {
+	return se.Err
+}
 
-	// Get top 2 synonyms as hints
-	for i, w := range words {
-		if i >= 2 || len(hints) >= 2 {
-			break
-		}
-		hints = append(hints, fmt.Sprintf("Similar to: %s", w.Word))
-	}
+// HintResult holds the merged hints together with the per-source error report
+type HintResult struct {
+	Word   string        `json:"word"`
+	Hints  []Hint        `json:"hints"`
+	Errors []SourceError `json:"errors,omitempty"`
+	Leaks  int           `json:"leaks,omitempty"`
 
-	return hints, nil
+	// Fallbacks describes where the difficulty filter had to use harder or unknown words
+	Fallbacks []string `json:"fallbacks,omitempty"`
 }
 
-// getSoundsLikeHints gets "sounds like" hints
-func (hg *HintGenerator) getSoundsLikeHints(word string) ([]string, error) {
-	url := fmt.Sprintf("https://api.datamuse.com/words?sl=%s", word)
+// Ladder returns the hints ordered from least to most revealing
+func (hr *HintResult) Ladder() *HintLadder {
+	return NewHintLadder(hr.Word, hr.Hints)
+}
 
-	resp, err := hg.httpClient.Get(url)
+// GenerateHints creates a list of hints for the given word.
+// All selected sources are queried concurrently under the deadline of ctx
+// (optionally narrowed by opts.Timeout), and their hints are merged in the
+// sources' priority order. When there are more than opts.MaxHints, the
+// least revealing ones are kept. Hints that leak the answer are dropped and
+// counted in the result's Leaks. Failing sources are listed in the
+// result's Errors; the returned error is only set for invalid options.
+func (hg *HintGenerator) GenerateHints(ctx context.Context, word string, opts HintOptions) (*HintResult, error) {
+	word = normalizeWord(word)
+
+	sources, err := hg.selectSources(opts.Sources)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	var words []DataMuseWord
-	if err := json.NewDecoder(resp.Body).Decode(&words); err != nil {
-		return nil, err
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
 	}
 
-	hints := []string{}
+	results := make([][]Hint, len(sources))
+	errs := make([]error, len(sources))
 
-	// Only add "sounds like" if it's not too similar
-	for i, w := range words {
-		if i >= 2 || len(hints) >= 1 {
-			break
-		}
-		// Avoid returning words that are very close to original
-		if len(w.Word) > 3 && w.Word != word {
-			hints = append(hints, fmt.Sprintf("Sounds like: %s", w.Word))
-		}
+	var wg sync.WaitGroup
+	for i, source := range sources {
+		wg.Add(1)
+		go func(i int, source HintSource) {
+			defer wg.Done()
+			results[i], errs[i] = source.Hints(ctx, word)
+		}(i, source)
 	}
+	wg.Wait()
 
-	return hints, nil
-}
-
-// getRelatedWordHints gets related word hints
-func (hg *HintGenerator) getRelatedWordHints(word string) ([]string, error) {
-	url := fmt.Sprintf("https://api.datamuse.com/words?rel_trg=%s", word)
-
-	resp, err := hg.httpClient.Get(url)
-	if err != nil {
-		return nil, err
+	result := &HintResult{Word: word, Hints: []Hint{}}
+	for i, source := range sources {
+		if errs[i] != nil {
+			result.Errors = append(result.Errors, SourceError{
+				Source:  source.Name(),
+				Message: errs[i].Error(),
+				Err:     errs[i],
+			})
+		}
+		for _, hint := range results[i] {
+			if opts.MaxLevel > 0 && hint.Level > opts.MaxLevel {
+				continue
+			}
+			// Drop hints that give away the answer
+			if hg.leaks.HintLeaks(word, hint, opts.LeakStrictness) {
+				result.Leaks++
+				continue
+			}
+			if hint.Source == "" {
+				hint.Source = source.Name()
+			}
+			result.Hints = append(result.Hints, hint)
+		}
 	}
-	defer resp.Body.Close()
 
-	var words []DataMuseWord
-	if err := json.NewDecoder(resp.Body).Decode(&words); err != nil {
-		return nil, err
+	if hg.difficulty != nil {
+		result.Hints, result.Fallbacks = hg.difficulty.Filter(word, opts.Difficulty, result.Hints)
 	}
 
-	hints := []string{}
-
-	// Get a couple related words
-	for i, w := range words {
-		if i >= 3 || len(hints) >= 2 {
-			break
-		}
-		hints = append(hints, fmt.Sprintf("Think of: %s", w.Word))
+	if opts.MaxHints > 0 {
+		result.Hints = leastRevealing(result.Hints, opts.MaxHints)
 	}
 
-	return hints, nil
+	return result, nil
 }
 
-func main() {
-	hintGen := NewHintGenerator()
-
-	// Example word to generate hints for
-	word := "contractor"
-
-	// How many hints you want at max
-	maxHints := 5
-
-	hints, err := hintGen.GenerateHints(word, maxHints)
-	if err != nil {
-		log.Fatalf("Error generating hints: %v", err)
+// leastRevealing keeps the n hints with the lowest reveal levels, so a hint
+// ladder built from them still starts at its gentlest rungs. Ties go to the
+// higher priority source and the kept hints stay in merge order.
+func leastRevealing(hints []Hint, n int) []Hint {
+	if len(hints) <= n {
+		return hints
+	}
+	ranked := NewHintLadder("", hints).Rungs[:n]
+	cutoff := ranked[n-1].Level
+	atCutoff := 0
+	for _, hint := range ranked {
+		if hint.Level == cutoff {
+			atCutoff++
+		}
 	}
 
-	fmt.Printf("Hints for the word '%s':\n", word)
-	for i, hint := range hints {
-		fmt.Printf("%d. %s\n", i+1, hint)
+	kept := make([]Hint, 0, n)
+	for _, hint := range hints {
+		if hint.Level < cutoff || (hint.Level == cutoff && atCutoff > 0) {
+			if hint.Level == cutoff {
+				atCutoff--
+			}
+			kept = append(kept, hint)
+		}
 	}
+	return kept
 }
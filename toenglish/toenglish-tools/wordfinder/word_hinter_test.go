@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// stubSource returns fixed hints after an optional delay and counts its calls
+type stubSource struct {
+	name  string
+	hints []Hint
+	err   error
+	delay time.Duration
+	calls atomic.Int32
+}
+
+func (ss *stubSource) Name() string {
+	return ss.name
+}
+
+func (ss *stubSource) Hints(ctx context.Context, word string) ([]Hint, error) {
+	ss.calls.Add(1)
+	if ss.delay > 0 {
+		select {
+		case <-time.After(ss.delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return ss.hints, ss.err
+}
+
+// hintTexts lists the text of each hint
+func hintTexts(hints []Hint) string {
+	texts := make([]string, len(hints))
+	for i, hint := range hints {
+		texts[i] = hint.Text
+	}
+	return strings.Join(texts, "|")
+}
+
+func TestGenerateHintsMerge(t *testing.T) {
+	slow := &stubSource{name: "slow", delay: 30 * time.Millisecond, hints: []Hint{
+		NewHint(HintDefinition, "", "slow one"),
+		NewHint(HintRelated, "", "slow two"),
+	}}
+	fast := &stubSource{name: "fast", hints: []Hint{NewHint(HintSynonym, "", "fast one")}}
+	broken := &stubSource{name: "broken", err: errors.New("service down")}
+	partial := &stubSource{name: "partial", err: errors.New("page 2 failed"), hints: []Hint{
+		NewHint(HintDefinition, "elsewhere", "partial one"),
+	}}
+	generator := NewHintGenerator(slow, fast, broken, partial)
+
+	tests := []struct {
+		name    string
+		opts    HintOptions
+		want    string
+		errors  string
+		invalid bool
+	}{
+		// Merged in source priority order, whichever answers first
+		{"priority order", HintOptions{}, "slow one|slow two|fast one|partial one", "broken|partial", false},
+		{"picked order", HintOptions{Sources: []string{"fast", "slow"}}, "fast one|slow one|slow two", "", false},
+		{"max level", HintOptions{MaxLevel: 2}, "slow one|slow two|partial one", "broken|partial", false},
+		{"unknown source", HintOptions{Sources: []string{"fast", "nope"}}, "", "", true},
+
+		// The slow source misses the deadline and is reported like any other failure
+		{"timeout", HintOptions{Timeout: 5 * time.Millisecond}, "fast one|partial one", "slow|broken|partial", false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := generator.GenerateHints(context.Background(), " Word ", test.opts)
+			if test.invalid {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Word != "word" {
+				t.Errorf("word = %q, want the normalized word", result.Word)
+			}
+			if got := hintTexts(result.Hints); got != test.want {
+				t.Errorf("hints = %s, want %s", got, test.want)
+			}
+			names := []string{}
+			for _, sourceErr := range result.Errors {
+				names = append(names, sourceErr.Source)
+				if sourceErr.Message == "" || sourceErr.Err == nil {
+					t.Errorf("error from %s has no message", sourceErr.Source)
+				}
+			}
+			if got := strings.Join(names, "|"); got != test.errors {
+				t.Errorf("errors from %s, want %s", got, test.errors)
+			}
+		})
+	}
+}
+
+func TestGenerateHintsSourceName(t *testing.T) {
+	source := &stubSource{name: "stub", hints: []Hint{
+		NewHint(HintDefinition, "", "unnamed"),
+		NewHint(HintDefinition, "other", "named"),
+	}}
+	result, err := NewHintGenerator(source).GenerateHints(context.Background(), "word", HintOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Hints[0].Source != "stub" || result.Hints[1].Source != "other" {
+		t.Errorf("sources = %s, %s, want stub, other", result.Hints[0].Source, result.Hints[1].Source)
+	}
+}
+
+func TestGenerateHintsMaxHints(t *testing.T) {
+	source := &stubSource{name: "stub", hints: []Hint{
+		NewHint(HintAnagram, "", "anagram"),
+		NewHint(HintDefinition, "", "definition"),
+		NewHint(HintLength, "", "length"),
+		NewHint(HintRelated, "", "related"),
+		NewHint(HintSynonym, "", "synonym one"),
+		NewHint(HintSynonym, "", "synonym two"),
+	}}
+	generator := NewHintGenerator(source)
+
+	tests := []struct {
+		max  int
+		want string
+	}{
+		// The least revealing hints are kept, in merge order
+		{1, "related"},
+		{2, "definition|related"},
+		{3, "definition|related|synonym one"},
+		{5, "definition|length|related|synonym one|synonym two"},
+		{6, "anagram|definition|length|related|synonym one|synonym two"},
+		{0, "anagram|definition|length|related|synonym one|synonym two"},
+	}
+	for _, test := range tests {
+		result, err := generator.GenerateHints(context.Background(), "word", HintOptions{MaxHints: test.max})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hintTexts(result.Hints); got != test.want {
+			t.Errorf("MaxHints %d kept %s, want %s", test.max, got, test.want)
+		}
+	}
+}
+
+func TestGenerateHintsLeaks(t *testing.T) {
+	source := &stubSource{name: "stub", hints: []Hint{
+		NewHint(HintDefinition, "", "Definition: to abandon something"),
+		NewHint(HintSynonym, "", "Similar to: forsake"),
+	}}
+	generator := NewHintGenerator(source)
+
+	tests := []struct {
+		strictness LeakStrictness
+		leaks      int
+	}{
+		{LeakDefault, 1},
+		{LeakOff, 0},
+	}
+	for _, test := range tests {
+		result, err := generator.GenerateHints(context.Background(), "abandon", HintOptions{LeakStrictness: test.strictness})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Leaks != test.leaks || len(result.Hints) != 2-test.leaks {
+			t.Errorf("strictness %d: %d leaks and %d hints, want %d leaks", test.strictness, result.Leaks, len(result.Hints), test.leaks)
+		}
+	}
+}
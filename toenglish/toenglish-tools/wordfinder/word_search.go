@@ -0,0 +1,426 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+// WordSearchDirection is the reading direction of a hidden word
+type WordSearchDirection string
+
+// Supported word search directions
+const (
+	DirectionRight     WordSearchDirection = "right"
+	DirectionDown      WordSearchDirection = "down"
+	DirectionDownRight WordSearchDirection = "down-right"
+	DirectionUpRight   WordSearchDirection = "up-right"
+	DirectionLeft      WordSearchDirection = "left"
+	DirectionUp        WordSearchDirection = "up"
+	DirectionUpLeft    WordSearchDirection = "up-left"
+	DirectionDownLeft  WordSearchDirection = "down-left"
+)
+
+// wordSearchSteps gives the row and column step of each direction
+var wordSearchSteps = map[WordSearchDirection][2]int{
+	DirectionRight:     {0, 1},
+	DirectionDown:      {1, 0},
+	DirectionDownRight: {1, 1},
+	DirectionUpRight:   {-1, 1},
+	DirectionLeft:      {0, -1},
+	DirectionUp:        {-1, 0},
+	DirectionUpLeft:    {-1, -1},
+	DirectionDownLeft:  {1, -1},
+}
+
+// allWordSearchDirections lists every direction, forwards ones first
+var allWordSearchDirections = []WordSearchDirection{
+	DirectionRight, DirectionDown, DirectionDownRight, DirectionUpRight,
+	DirectionLeft, DirectionUp, DirectionUpLeft, DirectionDownLeft,
+}
+
+// ParseWordSearchDirections parses a comma-separated list such as "right,down,down-right".
+// "forward" stands for right, down and both rightward diagonals, "all" for all eight.
+func ParseWordSearchDirections(value string) ([]WordSearchDirection, error) {
+	directions := []WordSearchDirection{}
+	seen := map[WordSearchDirection]bool{}
+	add := func(direction WordSearchDirection) {
+		if !seen[direction] {
+			seen[direction] = true
+			directions = append(directions, direction)
+		}
+	}
+
+	for _, name := range strings.Split(value, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch name {
+		case "":
+			continue
+		case "all":
+			for _, direction := range allWordSearchDirections {
+				add(direction)
+			}
+		case "forward":
+			for _, direction := range allWordSearchDirections[:4] {
+				add(direction)
+			}
+		default:
+			direction := WordSearchDirection(name)
+			if _, ok := wordSearchSteps[direction]; !ok {
+				return nil, fmt.Errorf("unknown word search direction '%s'", name)
+			}
+			add(direction)
+		}
+	}
+	if len(directions) == 0 {
+		return nil, fmt.Errorf("no word search directions given")
+	}
+	return directions, nil
+}
+
+// WordSearchWord is a hidden word and where it starts
+type WordSearchWord struct {
+	Word      string              `json:"word"`
+	Row       int                 `json:"row"`
+	Col       int                 `json:"col"`
+	Direction WordSearchDirection `json:"direction"`
+}
+
+// WordSearch is a filled letter grid with the words hidden in it
+type WordSearch struct {
+	Title      string           `json:"title"`
+	Category   string           `json:"category,omitempty"`
+	Difficulty int              `json:"difficulty,omitempty"`
+	Seed       int64            `json:"seed"`
+	Width      int              `json:"width"`
+	Height     int              `json:"height"`
+	Grid       []string         `json:"grid"`
+	Words      []WordSearchWord `json:"words"`
+
+	// Decoys are near misses of hidden words planted in the grid; they are not in the word list
+	Decoys []WordSearchWord `json:"decoys,omitempty"`
+}
+
+// WordSearchOptions controls the word search generator
+type WordSearchOptions struct {
+	Width  int
+	Height int
+
+	// Directions the words may read in
+	Directions []WordSearchDirection
+
+	// MinWords and MaxWords bound the number of hidden words
+	MinWords int
+	MaxWords int
+
+	// DecoyLetters are the letters filling the free cells; empty uses the letters
+	// of the hidden words, which makes them harder to spot
+	DecoyLetters string
+
+	// Decoys is the number of near misses, hidden words with one letter changed, to plant
+	Decoys int
+
+	// Seed makes the word choice, the layout and the filling reproducible
+	Seed int64
+
+	// MaxAttempts is the number of random positions tried per word
+	MaxAttempts int
+}
+
+// wordSearchLayouts is the number of layouts tried before giving up on duplicate readings
+const wordSearchLayouts = 10
+
+// DefaultWordSearchOptions hides 8 to 12 words reading right, down or diagonally down on a 12x12 grid
+var DefaultWordSearchOptions = WordSearchOptions{
+	Width:       12,
+	Height:      12,
+	Directions:  []WordSearchDirection{DirectionRight, DirectionDown, DirectionDownRight},
+	MinWords:    8,
+	MaxWords:    12,
+	Decoys:      2,
+	MaxAttempts: 200,
+}
+
+// wordSearchLayout is the grid being filled. Fixed cells belong to a hidden word or a decoy.
+type wordSearchLayout struct {
+	letters [][]byte
+	fixed   [][]bool
+	random  *rand.Rand
+	opts    WordSearchOptions
+}
+
+// GenerateWordSearch hides words from the entries in a letter grid. Words are
+// tried in a seeded random order and may cross where they share a letter.
+func GenerateWordSearch(entries []VocabularyEntry, opts WordSearchOptions) (*WordSearch, error) {
+	if opts.Width < 3 || opts.Height < 3 || opts.MinWords <= 0 || opts.MaxWords < opts.MinWords {
+		return nil, fmt.Errorf("invalid word search options: %d to %d words on a %dx%d grid", opts.MinWords, opts.MaxWords, opts.Width, opts.Height)
+	}
+	if len(opts.Directions) == 0 {
+		return nil, fmt.Errorf("no word search directions given")
+	}
+	for _, letter := range strings.ToUpper(opts.DecoyLetters) {
+		if letter < 'A' || letter > 'Z' {
+			return nil, fmt.Errorf("decoy letters must be letters A-Z, got %q", opts.DecoyLetters)
+		}
+	}
+	random := rand.New(rand.NewSource(opts.Seed))
+
+	words := []string{}
+	seen := map[string]bool{}
+	for _, entry := range entries {
+		word := crosswordAnswer(entry.Word, max(opts.Width, opts.Height))
+		if word != "" && !seen[word] {
+			seen[word] = true
+			words = append(words, word)
+		}
+	}
+	random.Shuffle(len(words), func(i, j int) {
+		words[i], words[j] = words[j], words[i]
+	})
+	if len(words) > 2*opts.MaxWords {
+		words = words[:2*opts.MaxWords]
+	}
+
+	// Long words are the hardest to fit, so they go in first
+	sort.SliceStable(words, func(i, j int) bool {
+		return len(words[i]) > len(words[j])
+	})
+
+	for attempt := 0; attempt < wordSearchLayouts; attempt++ {
+		layout, placed, decoys, err := layOutWordSearch(words, seen, opts, random)
+		if err != nil {
+			return nil, err
+		}
+		// Redrawing the free cells cannot help when the hidden words and decoys
+		// already spell a hidden word twice, so that layout is dropped
+		if layout.hasDuplicates(placed) || !layout.fill(placed) {
+			continue
+		}
+
+		sort.Slice(placed, func(i, j int) bool {
+			return placed[i].Word < placed[j].Word
+		})
+		return &WordSearch{
+			Seed:   opts.Seed,
+			Width:  opts.Width,
+			Height: opts.Height,
+			Grid:   layout.rows(),
+			Words:  placed,
+			Decoys: decoys,
+		}, nil
+	}
+	return nil, fmt.Errorf("every one of %d layouts spells a hidden word twice; try another seed, more decoy letters or a larger grid", wordSearchLayouts)
+}
+
+// layOutWordSearch hides the words and plants the decoys on an empty grid
+func layOutWordSearch(words []string, seen map[string]bool, opts WordSearchOptions, random *rand.Rand) (*wordSearchLayout, []WordSearchWord, []WordSearchWord, error) {
+	layout := newWordSearchLayout(opts, random)
+	placed := []WordSearchWord{}
+	for _, word := range words {
+		if len(placed) >= opts.MaxWords {
+			break
+		}
+		// A word inside another one would be found twice
+		if containsHidden(placed, word) {
+			continue
+		}
+		if hidden, ok := layout.place(word); ok {
+			placed = append(placed, hidden)
+		}
+	}
+	if len(placed) < opts.MinWords {
+		return nil, nil, nil, fmt.Errorf("could only hide %d of %d words, need at least %d", len(placed), len(words), opts.MinWords)
+	}
+
+	decoys := []WordSearchWord{}
+	for i := 0; len(decoys) < opts.Decoys && i < len(placed); i++ {
+		decoy := nearMiss(placed[i].Word, random)
+		if seen[decoy] {
+			continue
+		}
+		if hidden, ok := layout.place(decoy); ok {
+			decoys = append(decoys, hidden)
+		}
+	}
+	return layout, placed, decoys, nil
+}
+
+// newWordSearchLayout creates an empty grid
+func newWordSearchLayout(opts WordSearchOptions, random *rand.Rand) *wordSearchLayout {
+	layout := &wordSearchLayout{random: random, opts: opts}
+	layout.letters = make([][]byte, opts.Height)
+	layout.fixed = make([][]bool, opts.Height)
+	for row := range layout.letters {
+		layout.letters[row] = make([]byte, opts.Width)
+		layout.fixed[row] = make([]bool, opts.Width)
+	}
+	return layout
+}
+
+// place writes the word at a random position where it fits, crossing the words
+// already placed only where they share a letter
+func (wl *wordSearchLayout) place(word string) (WordSearchWord, bool) {
+	attempts := wl.opts.MaxAttempts
+	if attempts <= 0 {
+		attempts = DefaultWordSearchOptions.MaxAttempts
+	}
+	for attempt := 0; attempt < attempts; attempt++ {
+		direction := wl.opts.Directions[wl.random.Intn(len(wl.opts.Directions))]
+		row, col := wl.random.Intn(wl.opts.Height), wl.random.Intn(wl.opts.Width)
+		if !wl.fits(word, row, col, direction) {
+			continue
+		}
+		step := wordSearchSteps[direction]
+		for i := 0; i < len(word); i++ {
+			r, c := row+i*step[0], col+i*step[1]
+			wl.letters[r][c] = word[i]
+			wl.fixed[r][c] = true
+		}
+		return WordSearchWord{Word: word, Row: row, Col: col, Direction: direction}, true
+	}
+	return WordSearchWord{}, false
+}
+
+// fits reports whether the word can be written from row, col in the direction
+func (wl *wordSearchLayout) fits(word string, row, col int, direction WordSearchDirection) bool {
+	step := wordSearchSteps[direction]
+	for i := 0; i < len(word); i++ {
+		r, c := row+i*step[0], col+i*step[1]
+		if r < 0 || r >= wl.opts.Height || c < 0 || c >= wl.opts.Width {
+			return false
+		}
+		if wl.letters[r][c] != 0 && wl.letters[r][c] != word[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// fill puts decoy letters into the free cells, redrawing them where they
+// would spell a hidden word a second time. It reports false when every draw did.
+func (wl *wordSearchLayout) fill(placed []WordSearchWord) bool {
+	alphabet := strings.ToUpper(wl.opts.DecoyLetters)
+	if alphabet == "" {
+		for _, hidden := range placed {
+			alphabet += hidden.Word
+		}
+	}
+
+	free := [][2]int{}
+	for row := range wl.letters {
+		for col := range wl.letters[row] {
+			if !wl.fixed[row][col] {
+				free = append(free, [2]int{row, col})
+			}
+		}
+	}
+	draw := func() {
+		for _, cell := range free {
+			wl.letters[cell[0]][cell[1]] = alphabet[wl.random.Intn(len(alphabet))]
+		}
+	}
+
+	draw()
+	for attempt := 0; attempt < 20 && wl.hasDuplicates(placed); attempt++ {
+		draw()
+	}
+	return !wl.hasDuplicates(placed)
+}
+
+// hasDuplicates reports whether any hidden word can be read more than once
+func (wl *wordSearchLayout) hasDuplicates(placed []WordSearchWord) bool {
+	for _, hidden := range placed {
+		if wl.occurrences(hidden.Word) > 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// occurrences counts the places the word can be read in any direction
+func (wl *wordSearchLayout) occurrences(word string) int {
+	count := 0
+	for row := range wl.letters {
+		for col := range wl.letters[row] {
+			for _, direction := range allWordSearchDirections {
+				if wl.reads(word, row, col, direction) {
+					count++
+				}
+			}
+		}
+	}
+
+	// A palindrome reads the same both ways from opposite ends
+	if isPalindrome(word) {
+		count /= 2
+	}
+	return count
+}
+
+// reads reports whether the word is spelled from row, col in the direction
+func (wl *wordSearchLayout) reads(word string, row, col int, direction WordSearchDirection) bool {
+	step := wordSearchSteps[direction]
+	for i := 0; i < len(word); i++ {
+		r, c := row+i*step[0], col+i*step[1]
+		if r < 0 || r >= wl.opts.Height || c < 0 || c >= wl.opts.Width || wl.letters[r][c] != word[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// rows returns the grid as strings
+func (wl *wordSearchLayout) rows() []string {
+	rows := make([]string, len(wl.letters))
+	for row, letters := range wl.letters {
+		rows[row] = string(letters)
+	}
+	return rows
+}
+
+// nearMiss changes one inner letter of the word, so the decoy starts and ends like it
+func nearMiss(word string, random *rand.Rand) string {
+	letters := []byte(word)
+	i := len(letters) / 2
+	if len(letters) > 2 {
+		i = 1 + random.Intn(len(letters)-2)
+	}
+	replacement := byte('A' + random.Intn(25))
+	if replacement >= letters[i] {
+		replacement++
+	}
+	letters[i] = replacement
+	return string(letters)
+}
+
+// containsHidden reports whether the word and a hidden word contain one another, either way round
+func containsHidden(placed []WordSearchWord, word string) bool {
+	reversed := reverseString(word)
+	for _, hidden := range placed {
+		if strings.Contains(hidden.Word, word) || strings.Contains(hidden.Word, reversed) ||
+			strings.Contains(word, hidden.Word) || strings.Contains(reversed, hidden.Word) {
+			return true
+		}
+	}
+	return false
+}
+
+// reverseString reverses an ASCII word
+func reverseString(word string) string {
+	letters := []byte(word)
+	for i, j := 0, len(letters)-1; i < j; i, j = i+1, j-1 {
+		letters[i], letters[j] = letters[j], letters[i]
+	}
+	return string(letters)
+}
+
+// isPalindrome reports whether the word reads the same backwards
+func isPalindrome(word string) bool {
+	for i, j := 0, len(word)-1; i < j; i, j = i+1, j-1 {
+		if word[i] != word[j] {
+			return false
+		}
+	}
+	return true
+}
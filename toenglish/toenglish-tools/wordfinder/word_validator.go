@@ -0,0 +1,272 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Verdict is a checker's opinion on whether a word is a real English word
+type Verdict string
+
+// Supported verdicts
+const (
+	VerdictValid   Verdict = "valid"
+	VerdictInvalid Verdict = "invalid"
+	VerdictUnknown Verdict = "unknown"
+)
+
+// CheckResult is the outcome of a single checker
+type CheckResult struct {
+	Checker     string   `json:"checker"`
+	Verdict     Verdict  `json:"verdict"`
+	Confidence  float64  `json:"confidence"`
+	POSTags     []string `json:"posTags,omitempty"`
+	Suggestions []string `json:"suggestions,omitempty"`
+	Error       string   `json:"error,omitempty"`
+}
+
+// WordChecker interface defines the methods any word validity checker must implement
+type WordChecker interface {
+	// Name returns the name of the checker
+	Name() string
+
+	// Check gives a verdict on the word together with its confidence between 0 and 1
+	Check(ctx context.Context, word string) (CheckResult, error)
+}
+
+// FallbackChecker uses a local checker whenever the primary (usually remote) checker fails
+type FallbackChecker struct {
+	primary  WordChecker
+	fallback WordChecker
+}
+
+// NewFallbackChecker creates a checker that falls back when primary returns an error
+func NewFallbackChecker(primary, fallback WordChecker) *FallbackChecker {
+	return &FallbackChecker{primary: primary, fallback: fallback}
+}
+
+// Name returns the name of the checker
+func (fc *FallbackChecker) Name() string {
+	return fc.primary.Name()
+}
+
+// Check runs the primary checker and falls back to the local checker on failure
+func (fc *FallbackChecker) Check(ctx context.Context, word string) (CheckResult, error) {
+	result, err := fc.primary.Check(ctx, word)
+	if err == nil {
+		return result, nil
+	}
+
+	fallbackResult, fallbackErr := fc.fallback.Check(ctx, word)
+	if fallbackErr != nil {
+		return result, fmt.Errorf("%v (fallback %s: %v)", err, fc.fallback.Name(), fallbackErr)
+	}
+	fallbackResult.Checker = fmt.Sprintf("%s (fallback for %s)", fc.fallback.Name(), fc.primary.Name())
+	fallbackResult.Error = err.Error()
+	return fallbackResult, nil
+}
+
+// ValidationReport combines the results of every checker in the chain
+type ValidationReport struct {
+	Word       string        `json:"word"`
+	Verdict    Verdict       `json:"verdict"`
+	Confidence float64       `json:"confidence"`
+	POSTags    []string      `json:"posTags,omitempty"`
+	Results    []CheckResult `json:"results"`
+}
+
+// WordValidator runs a chain of checkers and combines their verdicts
+type WordValidator struct {
+	checkers []WordChecker
+
+	// StopConfidence ends the chain early once a checker is at least this
+	// confident. Zero always runs every checker.
+	StopConfidence float64
+}
+
+// NewWordValidator creates a validator running the checkers in the given order
+func NewWordValidator(checkers ...WordChecker) *WordValidator {
+	return &WordValidator{checkers: checkers}
+}
+
+// Validate runs the checker chain and combines the verdicts, weighting each by its confidence
+func (wv *WordValidator) Validate(ctx context.Context, word string) *ValidationReport {
+	word = normalizeWord(word)
+	report := &ValidationReport{Word: word, Verdict: VerdictUnknown, Results: []CheckResult{}}
+
+	seenTags := map[string]bool{}
+	for _, checker := range wv.checkers {
+		result, err := checker.Check(ctx, word)
+		if err != nil {
+			result = CheckResult{
+				Checker: checker.Name(),
+				Verdict: VerdictUnknown,
+				Error:   err.Error(),
+			}
+		}
+		if result.Checker == "" {
+			result.Checker = checker.Name()
+		}
+		report.Results = append(report.Results, result)
+
+		for _, tag := range result.POSTags {
+			if !seenTags[tag] {
+				seenTags[tag] = true
+				report.POSTags = append(report.POSTags, tag)
+			}
+		}
+
+		if wv.StopConfidence > 0 && result.Verdict != VerdictUnknown && result.Confidence >= wv.StopConfidence {
+			break
+		}
+	}
+
+	report.Verdict, report.Confidence = combineVerdicts(report.Results)
+	return report
+}
+
+// combineVerdicts picks the verdict with the most confidence behind it.
+// Agreeing checkers reinforce each other (1 - product of their doubts), and
+// the result is scaled down by the share of confidence that disagreed.
+func combineVerdicts(results []CheckResult) (Verdict, float64) {
+	var validScore, invalidScore float64
+	validDoubt, invalidDoubt := 1.0, 1.0
+	for _, result := range results {
+		switch result.Verdict {
+		case VerdictValid:
+			validScore += result.Confidence
+			validDoubt *= 1 - result.Confidence
+		case VerdictInvalid:
+			invalidScore += result.Confidence
+			invalidDoubt *= 1 - result.Confidence
+		}
+	}
+
+	total := validScore + invalidScore
+	if total == 0 {
+		return VerdictUnknown, 0
+	}
+	if validScore >= invalidScore {
+		return VerdictValid, (1 - validDoubt) * (validScore / total)
+	}
+	return VerdictInvalid, (1 - invalidDoubt) * (invalidScore / total)
+}
+
+// VocabularyChecker accepts words from the local categorized vocabulary
+type VocabularyChecker struct {
+	vocabulary *Vocabulary
+}
+
+// NewVocabularyChecker creates a checker backed by the categorized vocabulary
+func NewVocabularyChecker(vocabulary *Vocabulary) *VocabularyChecker {
+	return &VocabularyChecker{vocabulary: vocabulary}
+}
+
+// Name returns the name of the checker
+func (vc *VocabularyChecker) Name() string {
+	return "vocabulary"
+}
+
+// Check marks vocabulary words as valid; other words are unknown rather than invalid
+func (vc *VocabularyChecker) Check(ctx context.Context, word string) (CheckResult, error) {
+	if _, ok := vc.vocabulary.Lookup(word); ok {
+		return CheckResult{Checker: vc.Name(), Verdict: VerdictValid, Confidence: 0.9}, nil
+	}
+	return CheckResult{Checker: vc.Name(), Verdict: VerdictUnknown}, nil
+}
+
+// SpellModelChecker checks words against the offline fuzzy spell model
+type SpellModelChecker struct {
+	spell *SpellChecker
+}
+
+// NewSpellModelChecker creates a checker backed by a trained spell model
+func NewSpellModelChecker(spell *SpellChecker) *SpellModelChecker {
+	return &SpellModelChecker{spell: spell}
+}
+
+// Name returns the name of the checker
+func (smc *SpellModelChecker) Name() string {
+	return "spell-model"
+}
+
+// Check marks words known to the spell model as valid and suggests corrections otherwise.
+// The model only knows our own corpus, so an unknown word is a weaker signal.
+func (smc *SpellModelChecker) Check(ctx context.Context, word string) (CheckResult, error) {
+	if smc.spell.Check(word) {
+		return CheckResult{Checker: smc.Name(), Verdict: VerdictValid, Confidence: 0.7}, nil
+	}
+	return CheckResult{
+		Checker:     smc.Name(),
+		Verdict:     VerdictInvalid,
+		Confidence:  0.5,
+		Suggestions: smc.spell.Suggest(word, 5),
+	}, nil
+}
+
+// runValidateCommand checks whether words are real English words before they are added
+func runValidateCommand(args []string) error {
+	fs := flag.NewFlagSet("validate", flag.ExitOnError)
+	modelPath := fs.String("model", "spell_model.json", "spell model file")
+	vocabPath := fs.String("vocab", "../wordcategorizer/clustered_with_difficulty.json", "categorized vocabulary used as local fallback")
+	offline := fs.Bool("offline", false, "skip the remote dictionary")
+	stop := fs.Float64("stop", 0, "stop the chain once a checker is this confident (0 runs every checker)")
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		return fmt.Errorf("usage: wordfinder validate [-offline] <word>...")
+	}
+
+	checkers := []WordChecker{}
+
+	// The local vocabulary stands in for the remote dictionary when it is unreachable or skipped
+	vocabulary, err := LoadVocabulary(*vocabPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: vocabulary unavailable: %v\n", err)
+	}
+	switch {
+	case !*offline && vocabulary != nil:
+		checkers = append(checkers, NewFallbackChecker(NewDictionaryAPIChecker(), NewVocabularyChecker(vocabulary)))
+	case !*offline:
+		checkers = append(checkers, NewDictionaryAPIChecker())
+	case vocabulary != nil:
+		checkers = append(checkers, NewVocabularyChecker(vocabulary))
+	}
+
+	checkers = append(checkers, NewPOSTagChecker())
+
+	if spell, err := LoadSpellChecker(*modelPath); err == nil {
+		checkers = append(checkers, NewSpellModelChecker(spell))
+	} else {
+		fmt.Fprintf(os.Stderr, "Warning: spell model unavailable: %v\n", err)
+	}
+
+	validator := NewWordValidator(checkers...)
+	validator.StopConfidence = *stop
+
+	for _, word := range fs.Args() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		report := validator.Validate(ctx, word)
+		cancel()
+
+		fmt.Printf("'%s': %s (confidence %.2f)\n", report.Word, report.Verdict, report.Confidence)
+		if len(report.POSTags) > 0 {
+			fmt.Printf("  Part of speech: %s\n", strings.Join(report.POSTags, ", "))
+		}
+		for _, result := range report.Results {
+			fmt.Printf("  - %s: %s (%.2f)", result.Checker, result.Verdict, result.Confidence)
+			if len(result.Suggestions) > 0 {
+				fmt.Printf(" did you mean: %s", strings.Join(result.Suggestions, ", "))
+			}
+			if result.Error != "" {
+				fmt.Printf(" [%s]", result.Error)
+			}
+			fmt.Println()
+		}
+	}
+	return nil
+}